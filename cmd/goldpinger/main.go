@@ -121,32 +121,56 @@ func main() {
 		logger.Info("Using configured namespace", zap.String("namespace", *goldpinger.GoldpingerConfig.Namespace))
 	}
 
-	// make a kubernetes client
-	var config *rest.Config
-	if goldpinger.GoldpingerConfig.KubeConfigPath == "" {
-		logger.Info("Kubeconfig not specified, trying to use in cluster config")
-		config, err = rest.InClusterConfig()
-	} else {
-		logger.Info("Kubeconfig specified", zap.String("path", goldpinger.GoldpingerConfig.KubeConfigPath))
-		config, err = clientcmd.BuildConfigFromFlags("", goldpinger.GoldpingerConfig.KubeConfigPath)
+	if err := goldpinger.LoadDiscoverySources(); err != nil {
+		logger.Fatal("Invalid discovery sources", zap.Error(err))
 	}
-	if err != nil {
-		logger.Fatal("Error getting config ", zap.Error(err))
+
+	if err := goldpinger.LoadFederationConfig(); err != nil {
+		logger.Fatal("Invalid federation config", zap.Error(err))
 	}
-	// communicate to kube-apiserver with protobuf
-	config.AcceptContentTypes = strings.Join([]string{runtime.ContentTypeProtobuf, runtime.ContentTypeJSON}, ",")
-	config.ContentType = runtime.ContentTypeProtobuf
 
-	// create the clientset
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		logger.Fatal("kubernetes.NewForConfig error ", zap.Error(err))
+	if err := goldpinger.LoadAuthenticators(); err != nil {
+		logger.Fatal("Invalid authentication", zap.Error(err))
+	}
+
+	if goldpinger.NeedsKubernetesClient() {
+		// make a kubernetes client
+		var config *rest.Config
+		if goldpinger.GoldpingerConfig.KubeConfigPath == "" {
+			logger.Info("Kubeconfig not specified, trying to use in cluster config")
+			config, err = rest.InClusterConfig()
+		} else {
+			logger.Info("Kubeconfig specified", zap.String("path", goldpinger.GoldpingerConfig.KubeConfigPath))
+			config, err = clientcmd.BuildConfigFromFlags("", goldpinger.GoldpingerConfig.KubeConfigPath)
+		}
+		if err != nil {
+			logger.Fatal("Error getting config ", zap.Error(err))
+		}
+		// communicate to kube-apiserver with protobuf
+		config.AcceptContentTypes = strings.Join([]string{runtime.ContentTypeProtobuf, runtime.ContentTypeJSON}, ",")
+		config.ContentType = runtime.ContentTypeProtobuf
+
+		// create the clientset
+		clientset, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			logger.Fatal("kubernetes.NewForConfig error ", zap.Error(err))
+		}
+		goldpinger.GoldpingerConfig.KubernetesClient = clientset
+	} else {
+		logger.Info("No kubernetes discovery source or authentication, not creating a kubernetes client")
+	}
+
+	if err := goldpinger.LoadPeerTLS(); err != nil {
+		logger.Fatal("Invalid peer TLS", zap.Error(err))
 	}
-	goldpinger.GoldpingerConfig.KubernetesClient = clientset
 
 	// Check if we have an override for the client, default to own port
 	if goldpinger.GoldpingerConfig.Port == 0 {
-		goldpinger.GoldpingerConfig.Port = server.Port
+		if goldpinger.PeerTLSEnabled() {
+			goldpinger.GoldpingerConfig.Port = server.TLSPort
+		} else {
+			goldpinger.GoldpingerConfig.Port = server.Port
+		}
 	}
 
 	if goldpinger.GoldpingerConfig.PodIP == "" {
@@ -166,6 +190,22 @@ func main() {
 		logger.Error("Unknown IP version specified: expected values are 4 or 6", zap.Strings("IPVersions", goldpinger.GoldpingerConfig.IPVersions))
 	}
 
+	if err := goldpinger.ValidatePingPaths(); err != nil {
+		logger.Fatal("Invalid ping paths", zap.Error(err))
+	}
+
+	if err := goldpinger.ValidatePeerSelection(); err != nil {
+		logger.Fatal("Invalid peer selection", zap.Error(err))
+	}
+
+	if err := goldpinger.ValidateUnavailablePeerPolicy(); err != nil {
+		logger.Fatal("Invalid unavailable peer policy", zap.Error(err))
+	}
+
+	if err := goldpinger.LoadProbeTargets(); err != nil {
+		logger.Fatal("Invalid probe targets", zap.Error(err))
+	}
+
 	// Handle deprecated flags
 	if int(goldpinger.GoldpingerConfig.PingTimeout) == 0 {
 		logger.Warn("ping-timeout-ms is deprecated in favor of ping-timeout and will be removed in the future",
@@ -184,6 +224,9 @@ func main() {
 	}
 
 	server.ConfigureAPI()
+	if err := goldpinger.StartGRPCServer(); err != nil {
+		logger.Fatal("Error serving gRPC", zap.Error(err))
+	}
 	goldpinger.StartUpdater()
 
 	logger.Info("All good, starting serving the API")
@@ -14,6 +14,7 @@ import (
 	"github.com/go-openapi/runtime"
 	cr "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 )
 
 // NewCheckAllPodsParams creates a new CheckAllPodsParams object,
@@ -58,6 +59,35 @@ func NewCheckAllPodsParamsWithHTTPClient(client *http.Client) *CheckAllPodsParam
    Typically these are written to a http.Request.
 */
 type CheckAllPodsParams struct {
+
+	/* Exclude.
+
+	   leave these peers out, by pod name, pod IP or host IP
+	*/
+	Exclude []string
+
+	/* Filter.
+
+	   only check the peers matching all these key=value filters, on their zone, region,
+	   source or role
+	*/
+	Filter []string
+
+	/* Include.
+
+	   only check these peers, by pod name, pod IP or host IP
+	*/
+	Include []string
+
+	/* TimeoutSeconds.
+
+	   how long to wait for the check, in seconds, up to the maximum set on the server.
+	   Defaults to the timeout set on the server
+
+	   Format: double
+	*/
+	TimeoutSeconds *float64
+
 	timeout    time.Duration
 	Context    context.Context
 	HTTPClient *http.Client
@@ -111,6 +141,50 @@ func (o *CheckAllPodsParams) SetHTTPClient(client *http.Client) {
 	o.HTTPClient = client
 }
 
+// WithExclude adds the exclude to the check all pods params
+func (o *CheckAllPodsParams) WithExclude(exclude []string) *CheckAllPodsParams {
+	o.SetExclude(exclude)
+	return o
+}
+
+// SetExclude adds the exclude to the check all pods params
+func (o *CheckAllPodsParams) SetExclude(exclude []string) {
+	o.Exclude = exclude
+}
+
+// WithFilter adds the filter to the check all pods params
+func (o *CheckAllPodsParams) WithFilter(filter []string) *CheckAllPodsParams {
+	o.SetFilter(filter)
+	return o
+}
+
+// SetFilter adds the filter to the check all pods params
+func (o *CheckAllPodsParams) SetFilter(filter []string) {
+	o.Filter = filter
+}
+
+// WithInclude adds the include to the check all pods params
+func (o *CheckAllPodsParams) WithInclude(include []string) *CheckAllPodsParams {
+	o.SetInclude(include)
+	return o
+}
+
+// SetInclude adds the include to the check all pods params
+func (o *CheckAllPodsParams) SetInclude(include []string) {
+	o.Include = include
+}
+
+// WithTimeoutSeconds adds the timeoutSeconds to the check all pods params
+func (o *CheckAllPodsParams) WithTimeoutSeconds(timeoutSeconds *float64) *CheckAllPodsParams {
+	o.SetTimeoutSeconds(timeoutSeconds)
+	return o
+}
+
+// SetTimeoutSeconds adds the timeoutSeconds to the check all pods params
+func (o *CheckAllPodsParams) SetTimeoutSeconds(timeoutSeconds *float64) {
+	o.TimeoutSeconds = timeoutSeconds
+}
+
 // WriteToRequest writes these params to a swagger request
 func (o *CheckAllPodsParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
 
@@ -119,8 +193,109 @@ func (o *CheckAllPodsParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.
 	}
 	var res []error
 
+	if o.Exclude != nil {
+
+		// binding items for exclude
+		joinedExclude := o.bindParamExclude(reg)
+
+		// query array param exclude
+		if err := r.SetQueryParam("exclude", joinedExclude...); err != nil {
+			return err
+		}
+	}
+
+	if o.Filter != nil {
+
+		// binding items for filter
+		joinedFilter := o.bindParamFilter(reg)
+
+		// query array param filter
+		if err := r.SetQueryParam("filter", joinedFilter...); err != nil {
+			return err
+		}
+	}
+
+	if o.Include != nil {
+
+		// binding items for include
+		joinedInclude := o.bindParamInclude(reg)
+
+		// query array param include
+		if err := r.SetQueryParam("include", joinedInclude...); err != nil {
+			return err
+		}
+	}
+
+	if o.TimeoutSeconds != nil {
+
+		// query param timeout
+		var qrTimeout float64
+
+		if o.TimeoutSeconds != nil {
+			qrTimeout = *o.TimeoutSeconds
+		}
+		qTimeout := swag.FormatFloat64(qrTimeout)
+		if qTimeout != "" {
+
+			if err := r.SetQueryParam("timeout", qTimeout); err != nil {
+				return err
+			}
+		}
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
+
+// bindParamCheckAllPods binds the parameter exclude
+func (o *CheckAllPodsParams) bindParamExclude(formats strfmt.Registry) []string {
+	excludeIR := o.Exclude
+
+	var excludeIC []string
+	for _, excludeIIR := range excludeIR { // explode []string
+
+		excludeIIV := excludeIIR // string as string
+		excludeIC = append(excludeIC, excludeIIV)
+	}
+
+	// items.CollectionFormat: "csv"
+	excludeIS := swag.JoinByFormat(excludeIC, "csv")
+
+	return excludeIS
+}
+
+// bindParamCheckAllPods binds the parameter filter
+func (o *CheckAllPodsParams) bindParamFilter(formats strfmt.Registry) []string {
+	filterIR := o.Filter
+
+	var filterIC []string
+	for _, filterIIR := range filterIR { // explode []string
+
+		filterIIV := filterIIR // string as string
+		filterIC = append(filterIC, filterIIV)
+	}
+
+	// items.CollectionFormat: "csv"
+	filterIS := swag.JoinByFormat(filterIC, "csv")
+
+	return filterIS
+}
+
+// bindParamCheckAllPods binds the parameter include
+func (o *CheckAllPodsParams) bindParamInclude(formats strfmt.Registry) []string {
+	includeIR := o.Include
+
+	var includeIC []string
+	for _, includeIIR := range includeIR { // explode []string
+
+		includeIIV := includeIIR // string as string
+		includeIC = append(includeIC, includeIIV)
+	}
+
+	// items.CollectionFormat: "csv"
+	includeIS := swag.JoinByFormat(includeIC, "csv")
+
+	return includeIS
+}
@@ -36,10 +36,20 @@ type ClientService interface {
 
 	ClusterHealth(params *ClusterHealthParams, opts ...ClientOption) (*ClusterHealthOK, error)
 
+	Debug(params *DebugParams, opts ...ClientOption) (*DebugOK, error)
+
+	Depart(params *DepartParams, opts ...ClientOption) (*DepartOK, error)
+
+	Federation(params *FederationParams, opts ...ClientOption) (*FederationOK, error)
+
 	Healthz(params *HealthzParams, opts ...ClientOption) (*HealthzOK, error)
 
 	Ping(params *PingParams, opts ...ClientOption) (*PingOK, error)
 
+	Readyz(params *ReadyzParams, opts ...ClientOption) (*ReadyzOK, error)
+
+	ZoneMatrix(params *ZoneMatrixParams, opts ...ClientOption) (*ZoneMatrixOK, error)
+
 	SetTransport(transport runtime.ClientTransport)
 }
 
@@ -157,6 +167,120 @@ func (a *Client) ClusterHealth(params *ClusterHealthParams, opts ...ClientOption
 	panic(msg)
 }
 
+/*
+  Debug Report the internal state of the pinger pipeline, for troubleshooting
+*/
+func (a *Client) Debug(params *DebugParams, opts ...ClientOption) (*DebugOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewDebugParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "debug",
+		Method:             "GET",
+		PathPattern:        "/debug/goldpinger",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &DebugReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*DebugOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for debug: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
+/*
+  Depart Notifies this instance that a peer is shutting down, so that it reports it as departed rather than unreachable
+*/
+func (a *Client) Depart(params *DepartParams, opts ...ClientOption) (*DepartOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewDepartParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "depart",
+		Method:             "POST",
+		PathPattern:        "/depart",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &DepartReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*DepartOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for depart: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
+/*
+  Federation Calls /cluster_health and /check_all on all the clusters of the federation, and aggregates their health and the latency between them
+*/
+func (a *Client) Federation(params *FederationParams, opts ...ClientOption) (*FederationOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewFederationParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "federation",
+		Method:             "GET",
+		PathPattern:        "/federation",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &FederationReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*FederationOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for federation: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 /*
   Healthz The healthcheck endpoint provides detailed information about the health of a web service. If each of the components required by the service are healthy, then the service is considered healthy and will return a 200 OK response. If any of the components needed by the service are unhealthy, then a 503 Service Unavailable response will be provided.
 */
@@ -233,6 +357,82 @@ func (a *Client) Ping(params *PingParams, opts ...ClientOption) (*PingOK, error)
 	panic(msg)
 }
 
+/*
+  Readyz The readiness endpoint succeeds once this instance discovered its peers, started pinging them and collected a first round of results. It reports the status of each component, and returns a 503 Service Unavailable response while any of them isn't ready.
+*/
+func (a *Client) Readyz(params *ReadyzParams, opts ...ClientOption) (*ReadyzOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewReadyzParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "readyz",
+		Method:             "GET",
+		PathPattern:        "/readyz",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &ReadyzReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*ReadyzOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for readyz: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
+/*
+  ZoneMatrix Calls /check on all the pods, and aggregates the results into the latency and loss between each pair of zones and regions
+*/
+func (a *Client) ZoneMatrix(params *ZoneMatrixParams, opts ...ClientOption) (*ZoneMatrixOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewZoneMatrixParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "zoneMatrix",
+		Method:             "GET",
+		PathPattern:        "/zone_matrix",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &ZoneMatrixReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*ZoneMatrixOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for zoneMatrix: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 // SetTransport changes the transport on the client
 func (a *Client) SetTransport(transport runtime.ClientTransport) {
 	a.transport = transport
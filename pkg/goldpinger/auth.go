@@ -0,0 +1,483 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"go.uber.org/zap"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// AuthModeToken authenticates the callers with static bearer tokens, read from --auth-tokens-file
+	AuthModeToken = "token"
+	// AuthModeKubernetes authenticates the callers' bearer tokens with a TokenReview, and authorizes
+	// them with a SubjectAccessReview on the non-resource URL they call
+	AuthModeKubernetes = "kubernetes"
+	// AuthModeMTLS authenticates the callers with the common name of their verified client certificate
+	AuthModeMTLS = "mtls"
+)
+
+// AuthIdentity is an authenticated caller
+type AuthIdentity struct {
+	// Mode is the mode that authenticated the caller
+	Mode string
+	// Name is the name of the caller: the name of its token, its user name, or its certificate's common name
+	Name string
+	// UID, Groups and Extra are filled in by the kubernetes mode
+	UID    string
+	Groups []string
+	Extra  map[string][]string
+	// token is the static token of the caller, filled in by the token mode
+	token *StaticToken
+}
+
+// Authenticator authenticates and authorizes the callers of the API
+type Authenticator interface {
+	// Authenticate returns the identity of the caller, or nil if the request carries no credentials for this authenticator
+	Authenticate(r *http.Request) (*AuthIdentity, error)
+	// Authorize tells whether an authenticated caller may make the request
+	Authorize(r *http.Request, identity *AuthIdentity) (bool, error)
+}
+
+// AuthenticatorFactory builds the authenticator of a mode, from the configuration
+type AuthenticatorFactory func() (Authenticator, error)
+
+// authenticatorFactories holds the registered authenticator factories, by mode
+var authenticatorFactories = make(map[string]AuthenticatorFactory)
+
+// authenticatorFactoriesMux controls concurrent access to authenticatorFactories
+var authenticatorFactoriesMux = sync.RWMutex{}
+
+// RegisterAuthenticator makes an authentication mode available to --auth-modes, replacing any
+// authenticator already registered for it.
+// It needs to be called before the authenticators are loaded, typically from an init function
+func RegisterAuthenticator(mode string, factory AuthenticatorFactory) {
+	authenticatorFactoriesMux.Lock()
+	defer authenticatorFactoriesMux.Unlock()
+	authenticatorFactories[mode] = factory
+}
+
+// getAuthModes returns the sorted list of registered authentication modes
+func getAuthModes() []string {
+	authenticatorFactoriesMux.RLock()
+	defer authenticatorFactoriesMux.RUnlock()
+	modes := make([]string, 0, len(authenticatorFactories))
+	for mode := range authenticatorFactories {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	return modes
+}
+
+func init() {
+	RegisterAuthenticator(AuthModeToken, newTokenAuthenticator)
+	RegisterAuthenticator(AuthModeKubernetes, newKubernetesAuthenticator)
+	RegisterAuthenticator(AuthModeMTLS, newMTLSAuthenticator)
+}
+
+// authenticator pairs an authenticator with its mode
+type authenticator struct {
+	mode string
+	Authenticator
+}
+
+// authenticators are the authenticators of --auth-modes, tried in order. The API is open when there are none
+var authenticators []authenticator
+
+// LoadAuthenticators builds the authenticators of --auth-modes
+func LoadAuthenticators() error {
+	authenticators = nil
+	for _, mode := range GoldpingerConfig.AuthModes {
+		authenticatorFactoriesMux.RLock()
+		factory, ok := authenticatorFactories[mode]
+		authenticatorFactoriesMux.RUnlock()
+		if !ok {
+			return fmt.Errorf("unknown authentication mode %q, must be one of %s", mode, strings.Join(getAuthModes(), ", "))
+		}
+		a, err := factory()
+		if err != nil {
+			return fmt.Errorf("authentication mode %s: %w", mode, err)
+		}
+		authenticators = append(authenticators, authenticator{mode: mode, Authenticator: a})
+	}
+	return nil
+}
+
+// hasAuthMode tells whether the given authentication mode is enabled
+func hasAuthMode(mode string) bool {
+	for _, m := range GoldpingerConfig.AuthModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// pathMatches tells whether a path matches one of the patterns: a pattern ending with a slash
+// matches all the paths under it, other patterns match a single path. The path is cleaned first,
+// so that dot segments and repeated slashes can't be used to match another pattern
+func pathMatches(patterns []string, urlPath string) bool {
+	cleaned := path.Clean("/" + urlPath)
+	if strings.HasSuffix(urlPath, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	for _, pattern := range patterns {
+		if pattern == cleaned || (strings.HasSuffix(pattern, "/") && pattern != "/" && strings.HasPrefix(cleaned, pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthMiddleware rejects the requests to the paths outside of --auth-open-paths that none of the
+// authenticators authenticates and authorizes
+func AuthMiddleware(next http.Handler) http.Handler {
+	if len(authenticators) == 0 {
+		return next
+	}
+	zap.L().Info("Added the authentication middleware", zap.Strings("modes", GoldpingerConfig.AuthModes))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if pathMatches(GoldpingerConfig.AuthOpenPaths, r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		logger := zap.L().With(
+			zap.String("path", r.URL.Path),
+			zap.String("remoteAddr", r.RemoteAddr),
+		)
+		for _, a := range authenticators {
+			identity, err := a.Authenticate(r)
+			if err != nil {
+				logger.Warn("Error authenticating request", zap.String("mode", a.mode), zap.Error(err))
+				CountError("auth")
+				continue
+			}
+			if identity == nil {
+				continue
+			}
+			identity.Mode = a.mode
+			allowed, err := a.Authorize(r, identity)
+			if err != nil {
+				logger.Warn("Error authorizing request", zap.String("mode", a.mode), zap.Error(err))
+				CountError("auth")
+				CountAuthRequest(a.mode, "error")
+				http.Error(w, "authorization failed", http.StatusInternalServerError)
+				return
+			}
+			if !allowed {
+				logger.Info("Forbidden request", zap.String("mode", a.mode), zap.String("name", identity.Name))
+				CountAuthRequest(a.mode, "forbidden")
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			CountAuthRequest(a.mode, "allowed")
+			next.ServeHTTP(w, r)
+			return
+		}
+		CountAuthRequest("", "unauthenticated")
+		w.Header().Set("WWW-Authenticate", `Bearer realm="goldpinger"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+// getBearerToken returns the bearer token of a request, if any
+func getBearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// StaticToken is a bearer token accepted by the token mode
+type StaticToken struct {
+	// Name identifies the caller in the logs and the metrics
+	Name string `json:"name"`
+	// Token is the bearer token
+	Token string `json:"token"`
+	// Paths restricts the paths the token may call, with the same syntax as --auth-open-paths.
+	// The token may call any path when empty
+	Paths []string `json:"paths,omitempty"`
+}
+
+// StaticTokens is the format of --auth-tokens-file
+type StaticTokens struct {
+	Tokens []StaticToken `json:"tokens"`
+}
+
+// tokenAuthenticator implements the token mode
+type tokenAuthenticator struct {
+	tokens []StaticToken
+}
+
+func newTokenAuthenticator() (Authenticator, error) {
+	if GoldpingerConfig.AuthTokensFile == "" {
+		return nil, fmt.Errorf("--auth-tokens-file is required")
+	}
+	data, err := os.ReadFile(GoldpingerConfig.AuthTokensFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read tokens file: %w", err)
+	}
+	var tokens StaticTokens
+	if err := yaml.UnmarshalStrict(data, &tokens); err != nil {
+		return nil, fmt.Errorf("could not parse tokens file: %w", err)
+	}
+	names := make(map[string]bool)
+	for i, token := range tokens.Tokens {
+		if token.Token == "" {
+			return nil, fmt.Errorf("token %d (%q) is empty", i, token.Name)
+		}
+		if token.Name == "" {
+			return nil, fmt.Errorf("token %d has no name", i)
+		}
+		if names[token.Name] {
+			return nil, fmt.Errorf("duplicate token name %q", token.Name)
+		}
+		names[token.Name] = true
+	}
+	return &tokenAuthenticator{tokens: tokens.Tokens}, nil
+}
+
+func (a *tokenAuthenticator) Authenticate(r *http.Request) (*AuthIdentity, error) {
+	bearer := getBearerToken(r)
+	if bearer == "" {
+		return nil, nil
+	}
+	for i := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(bearer), []byte(a.tokens[i].Token)) == 1 {
+			return &AuthIdentity{Name: a.tokens[i].Name, token: &a.tokens[i]}, nil
+		}
+	}
+	return nil, nil
+}
+
+// Authorize checks the paths of the very token the caller was authenticated with
+func (a *tokenAuthenticator) Authorize(r *http.Request, identity *AuthIdentity) (bool, error) {
+	token := identity.token
+	if token == nil {
+		return false, nil
+	}
+	return len(token.Paths) == 0 || pathMatches(token.Paths, r.URL.Path), nil
+}
+
+// authCacheEntry is a cached TokenReview or SubjectAccessReview
+type authCacheEntry struct {
+	identity *AuthIdentity
+	allowed  bool
+	expires  time.Time
+}
+
+// kubernetesAuthenticator implements the kubernetes mode, caching the reviews for --auth-cache-ttl
+type kubernetesAuthenticator struct {
+	mux      sync.Mutex
+	tokens   map[string]authCacheEntry
+	accesses map[string]authCacheEntry
+}
+
+func newKubernetesAuthenticator() (Authenticator, error) {
+	return &kubernetesAuthenticator{
+		tokens:   make(map[string]authCacheEntry),
+		accesses: make(map[string]authCacheEntry),
+	}, nil
+}
+
+// getCached returns an unexpired cache entry, dropping the expired ones
+func (a *kubernetesAuthenticator) getCached(cache map[string]authCacheEntry, key string) (authCacheEntry, bool) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	now := time.Now()
+	for k, entry := range cache {
+		if now.After(entry.expires) {
+			delete(cache, k)
+		}
+	}
+	entry, ok := cache[key]
+	return entry, ok
+}
+
+func (a *kubernetesAuthenticator) setCached(cache map[string]authCacheEntry, key string, entry authCacheEntry) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	entry.expires = time.Now().Add(GoldpingerConfig.AuthCacheTTL)
+	cache[key] = entry
+}
+
+func (a *kubernetesAuthenticator) Authenticate(r *http.Request) (*AuthIdentity, error) {
+	token := getBearerToken(r)
+	if token == "" {
+		return nil, nil
+	}
+	key := fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+	if entry, ok := a.getCached(a.tokens, key); ok {
+		return copyIdentity(entry.identity), nil
+	}
+
+	timer := GetLabeledKubernetesCallsTimer()
+	review, err := GoldpingerConfig.KubernetesClient.AuthenticationV1().TokenReviews().Create(
+		r.Context(),
+		&authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}},
+		metav1.CreateOptions{},
+	)
+	if err != nil {
+		CountError("kubernetes_api")
+		return nil, fmt.Errorf("token review failed: %w", err)
+	}
+	timer.ObserveDuration()
+
+	var identity *AuthIdentity
+	if review.Status.Authenticated {
+		identity = &AuthIdentity{
+			Name:   review.Status.User.Username,
+			UID:    review.Status.User.UID,
+			Groups: review.Status.User.Groups,
+		}
+		for k, v := range review.Status.User.Extra {
+			if identity.Extra == nil {
+				identity.Extra = make(map[string][]string)
+			}
+			identity.Extra[k] = v
+		}
+	}
+	a.setCached(a.tokens, key, authCacheEntry{identity: identity})
+	return copyIdentity(identity), nil
+}
+
+func (a *kubernetesAuthenticator) Authorize(r *http.Request, identity *AuthIdentity) (bool, error) {
+	verb := strings.ToLower(r.Method)
+	key := identity.Name + " " + verb + " " + r.URL.Path
+	if entry, ok := a.getCached(a.accesses, key); ok {
+		return entry.allowed, nil
+	}
+
+	review := authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   identity.Name,
+			UID:    identity.UID,
+			Groups: identity.Groups,
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+				Path: r.URL.Path,
+				Verb: verb,
+			},
+		},
+	}
+	for k, v := range identity.Extra {
+		if review.Spec.Extra == nil {
+			review.Spec.Extra = make(map[string]authorizationv1.ExtraValue)
+		}
+		review.Spec.Extra[k] = authorizationv1.ExtraValue(v)
+	}
+
+	timer := GetLabeledKubernetesCallsTimer()
+	result, err := GoldpingerConfig.KubernetesClient.AuthorizationV1().SubjectAccessReviews().Create(r.Context(), &review, metav1.CreateOptions{})
+	if err != nil {
+		CountError("kubernetes_api")
+		return false, fmt.Errorf("subject access review failed: %w", err)
+	}
+	timer.ObserveDuration()
+
+	a.setCached(a.accesses, key, authCacheEntry{allowed: result.Status.Allowed})
+	return result.Status.Allowed, nil
+}
+
+// copyIdentity returns a copy of a cached identity, so that the middleware can set its mode
+func copyIdentity(identity *AuthIdentity) *AuthIdentity {
+	if identity == nil {
+		return nil
+	}
+	c := *identity
+	return &c
+}
+
+// mtlsAuthenticator implements the mtls mode
+type mtlsAuthenticator struct{}
+
+func newMTLSAuthenticator() (Authenticator, error) {
+	return mtlsAuthenticator{}, nil
+}
+
+func (mtlsAuthenticator) Authenticate(r *http.Request) (*AuthIdentity, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+	return &AuthIdentity{Name: r.TLS.VerifiedChains[0][0].Subject.CommonName}, nil
+}
+
+func (mtlsAuthenticator) Authorize(r *http.Request, identity *AuthIdentity) (bool, error) {
+	if len(GoldpingerConfig.AuthClientNames) == 0 {
+		return true, nil
+	}
+	for _, name := range GoldpingerConfig.AuthClientNames {
+		if name == identity.Name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// clientToken caches the token of --auth-client-token-file
+var clientToken struct {
+	sync.Mutex
+	token  string
+	loaded time.Time
+}
+
+// clientTokenRefresh is how often the token of --auth-client-token-file is read again, to pick up rotations
+const clientTokenRefresh = time.Minute
+
+// clientTokenAuth sends the bearer token of --auth-client-token-file, if any. The token is read on each
+// call, so that the cached clients pick up its rotations
+var clientTokenAuth = runtime.ClientAuthInfoWriterFunc(func(req runtime.ClientRequest, _ strfmt.Registry) error {
+	if token := getClientToken(); token != "" {
+		return req.SetHeaderParam("Authorization", "Bearer "+token)
+	}
+	return nil
+})
+
+// getClientToken returns the bearer token to send to the peers, if any
+func getClientToken() string {
+	if GoldpingerConfig.AuthClientTokenFile == "" {
+		return ""
+	}
+	clientToken.Lock()
+	defer clientToken.Unlock()
+	if time.Since(clientToken.loaded) < clientTokenRefresh {
+		return clientToken.token
+	}
+	data, err := os.ReadFile(GoldpingerConfig.AuthClientTokenFile)
+	if err != nil {
+		zap.L().Error("Error reading client token", zap.Error(err))
+		CountError("auth")
+		return clientToken.token
+	}
+	clientToken.token = strings.TrimSpace(string(data))
+	clientToken.loaded = time.Now()
+	return clientToken.token
+}
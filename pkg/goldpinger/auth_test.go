@@ -0,0 +1,107 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPathMatches(t *testing.T) {
+	patterns := []string{"/ping", "/static/"}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/ping", true},
+		{"/ping/", false},
+		{"/check_all", false},
+		{"/static/", true},
+		{"/static/app.js", true},
+		{"/static", false},
+		{"//ping", true},
+		{"/static/../check_all", false},
+		{"/static/./../static/app.js", true},
+		{"/check_all/../ping", true},
+		{"/static/..", false},
+	}
+	for _, test := range tests {
+		if got := pathMatches(patterns, test.path); got != test.want {
+			t.Errorf("pathMatches(%q) = %t, want %t", test.path, got, test.want)
+		}
+	}
+}
+
+// newTestTokenAuthenticator returns a token authenticator for the given tokens file
+func newTestTokenAuthenticator(t *testing.T, tokens string) (Authenticator, error) {
+	file := filepath.Join(t.TempDir(), "tokens.yaml")
+	if err := os.WriteFile(file, []byte(tokens), 0600); err != nil {
+		t.Fatal(err)
+	}
+	previousTokensFile := GoldpingerConfig.AuthTokensFile
+	GoldpingerConfig.AuthTokensFile = file
+	defer func() { GoldpingerConfig.AuthTokensFile = previousTokensFile }()
+	return newTokenAuthenticator()
+}
+
+func TestNewTokenAuthenticatorRejectsAmbiguousNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens string
+	}{
+		{"no name", "tokens:\n  - token: a\n"},
+		{"duplicate name", "tokens:\n  - name: x\n    token: a\n  - name: x\n    token: b\n    paths: [/ping]\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := newTestTokenAuthenticator(t, test.tokens); err == nil {
+				t.Error("newTokenAuthenticator() accepted the tokens")
+			}
+		})
+	}
+}
+
+func TestTokenAuthenticatorAuthorizesTheMatchedToken(t *testing.T) {
+	a, err := newTestTokenAuthenticator(t, "tokens:\n  - name: dashboard\n    token: s3cr3t\n  - name: prometheus\n    token: 0th3r\n    paths: [/metrics]\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		token string
+		path  string
+		want  bool
+	}{
+		{"s3cr3t", "/check_all", true},
+		{"0th3r", "/metrics", true},
+		{"0th3r", "/check_all", false},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, test.path, nil)
+		r.Header.Set("Authorization", "Bearer "+test.token)
+		identity, err := a.Authenticate(r)
+		if err != nil || identity == nil {
+			t.Fatalf("Authenticate(%q) = %v, %v", test.token, identity, err)
+		}
+		if allowed, _ := a.Authorize(r, identity); allowed != test.want {
+			t.Errorf("Authorize(%q, %s) = %t, want %t", test.token, test.path, allowed, test.want)
+		}
+	}
+	if allowed, _ := a.Authorize(httptest.NewRequest(http.MethodGet, "/check_all", nil), &AuthIdentity{Name: "dashboard"}); allowed {
+		t.Error("Authorize() allowed an identity not authenticated with a token")
+	}
+}
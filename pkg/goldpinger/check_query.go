@@ -0,0 +1,144 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+)
+
+// peerCheckTimeoutShare is the share of its budget a peer is asked to answer /check within, leaving the
+// rest for the network
+const peerCheckTimeoutShare = 0.9
+
+// PeerFilter narrows down the peers a check covers, from the include, exclude and filter query parameters.
+// The zero value covers all the peers
+type PeerFilter struct {
+	// only the peers with one of these pod names, pod IPs or host IPs, if any
+	Include []string
+	// none of the peers with one of these pod names, pod IPs or host IPs
+	Exclude []string
+	// only the peers matching all these key=value filters, on their zone, region, source or role
+	Filter []string
+}
+
+// IsEmpty tells whether the filter covers all the peers
+func (f PeerFilter) IsEmpty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0 && len(f.Filter) == 0
+}
+
+// matches tells whether a peer passes the filter
+func (f PeerFilter) matches(name, podIP, hostIP string, attributes map[string]string) bool {
+	identifies := func(values []string) bool {
+		for _, value := range values {
+			if value == name || value == podIP || value == hostIP {
+				return true
+			}
+		}
+		return false
+	}
+	if len(f.Include) > 0 && !identifies(f.Include) {
+		return false
+	}
+	if identifies(f.Exclude) {
+		return false
+	}
+	for _, filter := range f.Filter {
+		key, value, _ := strings.Cut(filter, "=")
+		if attributes[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesPod tells whether a discovered pod passes the filter
+func (f PeerFilter) matchesPod(pod *GoldpingerPod) bool {
+	return f.matches(pod.Name, pod.PodIP, pod.HostIP, map[string]string{
+		"zone":   pod.Zone,
+		"region": pod.Region,
+		"source": pod.Source,
+		"role":   pod.Role,
+	})
+}
+
+// matchesResult tells whether the peer a ping result is for passes the filter
+func (f PeerFilter) matchesResult(podName string, result models.PodResult) bool {
+	return f.matches(podName, result.PodIP.String(), result.HostIP.String(), map[string]string{
+		"zone":   result.Zone,
+		"region": result.Region,
+		"source": result.Source,
+		"role":   result.Role,
+	})
+}
+
+// filterPods returns the pods passing the filter
+func (f PeerFilter) filterPods(pods map[string]*GoldpingerPod) map[string]*GoldpingerPod {
+	if f.IsEmpty() {
+		return pods
+	}
+	filtered := make(map[string]*GoldpingerPod)
+	for podName, pod := range pods {
+		if f.matchesPod(pod) {
+			filtered[podName] = pod
+		}
+	}
+	return filtered
+}
+
+// CheckQuery is what the query parameters of /check, /check_all, /cluster_health and /heatmap.png ask for
+type CheckQuery struct {
+	PeerFilter
+	// the timeout asked for, in seconds, if any
+	TimeoutSeconds *float64
+}
+
+// NewCheckQuery returns the check query for the bound parameters of a check endpoint
+func NewCheckQuery(timeoutSeconds *float64, include, exclude, filter []string) CheckQuery {
+	return CheckQuery{
+		PeerFilter: PeerFilter{
+			Include: include,
+			Exclude: exclude,
+			Filter:  filter,
+		},
+		TimeoutSeconds: timeoutSeconds,
+	}
+}
+
+// GetTimeout returns the timeout asked for, capped to maxTimeout, or defaultTimeout when none was asked for
+func (q CheckQuery) GetTimeout(defaultTimeout, maxTimeout time.Duration) time.Duration {
+	if q.TimeoutSeconds == nil {
+		return defaultTimeout
+	}
+	// also caps the NaN timeouts
+	if !(*q.TimeoutSeconds < maxTimeout.Seconds()) {
+		return maxTimeout
+	}
+	return time.Duration(*q.TimeoutSeconds * float64(time.Second))
+}
+
+// getPeerCheckTimeout returns how long a peer has to answer /check. When a timeout was asked for, it's
+// whatever is left of the deadline of the check, so that a deep check waits for the slow peers too
+func (q CheckQuery) getPeerCheckTimeout(ctx context.Context) time.Duration {
+	if q.TimeoutSeconds != nil {
+		if deadline, ok := ctx.Deadline(); ok {
+			return time.Until(deadline)
+		}
+	}
+	return GoldpingerConfig.CheckTimeout
+}
@@ -0,0 +1,140 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func float64Ptr(value float64) *float64 {
+	return &value
+}
+
+func TestCheckQueryGetTimeout(t *testing.T) {
+	tests := []struct {
+		name           string
+		timeoutSeconds *float64
+		want           time.Duration
+	}{
+		{"default", nil, time.Second},
+		{"below the maximum", float64Ptr(2.5), 2500 * time.Millisecond},
+		{"above the maximum", float64Ptr(30), 10 * time.Second},
+		{"overflowing", float64Ptr(1e300), 10 * time.Second},
+		{"NaN", float64Ptr(math.NaN()), 10 * time.Second},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := CheckQuery{TimeoutSeconds: test.timeoutSeconds}
+			if got := query.GetTimeout(time.Second, 10*time.Second); got != test.want {
+				t.Errorf("GetTimeout() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPeerFilterFilterPods(t *testing.T) {
+	pods := map[string]*GoldpingerPod{
+		"p1": {Name: "p1", PodIP: "10.0.0.1", HostIP: "192.168.0.1", Zone: "a", Role: RolePeer},
+		"p2": {Name: "p2", PodIP: "10.0.0.2", HostIP: "192.168.0.2", Zone: "b", Role: RolePeer},
+		"p3": {Name: "p3", PodIP: "10.0.0.3", HostIP: "192.168.0.3", Zone: "a", Role: RoleCanary},
+	}
+	tests := []struct {
+		name   string
+		filter PeerFilter
+		want   []string
+	}{
+		{"empty", PeerFilter{}, []string{"p1", "p2", "p3"}},
+		{"include by name and host IP", PeerFilter{Include: []string{"p1", "192.168.0.2"}}, []string{"p1", "p2"}},
+		{"exclude by pod IP", PeerFilter{Exclude: []string{"10.0.0.1"}}, []string{"p2", "p3"}},
+		{"include and exclude", PeerFilter{Include: []string{"p1", "p2"}, Exclude: []string{"p2"}}, []string{"p1"}},
+		{"filters", PeerFilter{Filter: []string{"zone=a", "role=peer"}}, []string{"p1"}},
+		{"unknown value", PeerFilter{Filter: []string{"region=x"}}, []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := []string{}
+			for podName := range test.filter.filterPods(pods) {
+				got = append(got, podName)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("filterPods() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckAllPodsForwardsTheQuery(t *testing.T) {
+	previousCheckTimeout := GoldpingerConfig.CheckTimeout
+	GoldpingerConfig.CheckTimeout = time.Second
+	defer func() { GoldpingerConfig.CheckTimeout = previousCheckTimeout }()
+
+	queries := make(chan url.Values, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	host, portString, _ := net.SplitHostPort(server.Listener.Addr().String())
+	port, _ := strconv.Atoi(portString)
+	pods := map[string]*GoldpingerPod{
+		"p1": {Name: "p1", PodIP: host, HostIP: host, Port: port, Role: RolePeer},
+	}
+
+	tests := []struct {
+		name        string
+		query       CheckQuery
+		ctxTimeout  time.Duration
+		wantMinimum float64
+		wantMaximum float64
+	}{
+		{"no timeout asked for", CheckQuery{}, 5 * time.Second, 0.9, 0.9},
+		{"deep check", CheckQuery{TimeoutSeconds: float64Ptr(30)}, 30 * time.Second, 26, 27},
+		{"quick check", CheckQuery{TimeoutSeconds: float64Ptr(0.5)}, 500 * time.Millisecond, 0.3, 0.45},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.query.PeerFilter = PeerFilter{Include: []string{"p1", "p2"}, Exclude: []string{"p3"}, Filter: []string{"zone=a"}}
+			ctx, cancel := context.WithTimeout(context.Background(), test.ctxTimeout)
+			defer cancel()
+
+			result := CheckAllPods(ctx, pods, test.query)
+			if response := result.Responses["p1"]; response.OK == nil || !*response.OK {
+				t.Fatalf("CheckAllPods() failed: %s", response.Error)
+			}
+			query := <-queries
+			timeoutSeconds, err := strconv.ParseFloat(query.Get("timeout"), 64)
+			if err != nil || timeoutSeconds < test.wantMinimum || timeoutSeconds > test.wantMaximum {
+				t.Errorf("timeout = %q, want between %v and %v", query.Get("timeout"), test.wantMinimum, test.wantMaximum)
+			}
+			for name, want := range map[string]string{"include": "p1,p2", "exclude": "p3", "filter": "zone=a"} {
+				if got := query.Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -32,50 +32,86 @@ import (
 )
 
 // CheckNeighbours queries the kubernetes API server for all other goldpinger pods
-// then calls Ping() on each one
-func CheckNeighbours(ctx context.Context) *models.CheckResults {
+// then calls Ping() on each one. Only the results of the peers passing the filter of the query are returned,
+// and the targets probed on the spot are given until the deadline of the context
+func CheckNeighbours(ctx context.Context, query CheckQuery) *models.CheckResults {
 	// Mux to prevent concurrent map address
 	checkResultsMux.Lock()
 	defer checkResultsMux.Unlock()
 	final := models.CheckResults{}
 	final.PodResults = make(map[string]models.PodResult)
+	now := time.Now()
 	for podName, podResult := range checkResults.PodResults {
-		final.PodResults[podName] = podResult
+		if !query.matchesResult(podName, podResult) {
+			continue
+		}
+		final.PodResults[podName] = stampPodResult(podResult, now)
 	}
-	final.ProbeResults = checkTargets()
+	final.ProbeResults = checkTargets(ctx)
 	return &final
 }
 
 // CheckNeighboursNeighbours queries the kubernetes API server for all other goldpinger
-// pods then calls Check() on each one
-func CheckNeighboursNeighbours(ctx context.Context) *models.CheckAllResults {
-	return CheckAllPods(ctx, SelectPods())
+// pods then calls Check() on each one passing the filter of the query
+func CheckNeighboursNeighbours(ctx context.Context, query CheckQuery) *models.CheckAllResults {
+	return CheckAllPods(ctx, query.filterPods(SelectPods()), query)
 }
 
-// CheckCluster does a CheckNeighboursNeighbours and analyses results to produce a binary OK or not OK
-func CheckCluster(ctx context.Context) *models.ClusterHealthResults {
+// CheckCluster does a CheckNeighboursNeighbours and analyses results to produce a binary OK or not OK.
+// Only the peers passing the filter of the query are checked and expected
+func CheckCluster(ctx context.Context, query CheckQuery) *models.ClusterHealthResults {
 	start := time.Now()
 	output := models.ClusterHealthResults{
 		GeneratedAt: strfmt.DateTime(start),
 		OK:          true,
 	}
-	selectedPods := SelectPods()
+	allPods := GetAllPods()
+	selectedPods := query.filterPods(selectPodsFor(getLocalPodKey(), allPods))
+	output.Coverage = getCoverage(allPods)
+
+	// the unavailable and departed nodes are reported separately, and aren't expected to be healthy
+	excusedNodes := make(map[string]bool)
+	for _, peer := range allPods {
+		if peer.Role == RoleGateway || excusedNodes[peer.HostIP] {
+			continue
+		}
+		if isDeparted(peer.PodIP) {
+			output.NodesDeparted = append(output.NodesDeparted, peer.HostIP)
+			excusedNodes[peer.HostIP] = true
+		} else if inMaintenance(peer) {
+			output.NodesMaintenance = append(output.NodesMaintenance, peer.HostIP)
+			excusedNodes[peer.HostIP] = true
+		}
+	}
+	sort.Strings(output.NodesDeparted)
+	sort.Strings(output.NodesMaintenance)
 
 	// precompute the expected set of nodes
 	expectedNodes := []string{}
 	for _, peer := range selectedPods {
-		expectedNodes = append(expectedNodes, peer.HostIP)
+		if peer.Role != RoleGateway && !excusedNodes[peer.HostIP] {
+			expectedNodes = append(expectedNodes, peer.HostIP)
+		}
 	}
 	sort.Strings(expectedNodes)
 
 	// get the response we serve for check_all
-	checkAll := CheckAllPods(ctx, selectedPods)
+	checkAll := CheckAllPods(ctx, selectedPods, query)
+
+	// count, for each node, how many of its peers see its clock as skewed
+	clockSkewVotes := make(map[string]int)
+	clockSkewObservers := make(map[string]int)
 
 	// we should at the very least have a response from ourselves
 	if len(checkAll.Responses) < 1 {
 		output.OK = false
 	}
 	for _, resp := range checkAll.Responses {
+		output.NodesTotal++
+		if excusedNodes[resp.HostIP.String()] {
+			// an unavailable or departed node may well fail, or see a stale set of peers
+			continue
+		}
 		// 1. check that all nodes report OK
 		if *resp.OK {
 			output.NodesHealthy = append(output.NodesHealthy, resp.HostIP.String())
@@ -83,7 +119,6 @@ func CheckCluster(ctx context.Context) *models.ClusterHealthResults {
 			output.NodesUnhealthy = append(output.NodesUnhealthy, resp.HostIP.String())
 			output.OK = false
 		}
-		output.NodesTotal++
 		// 2. check that all nodes report the expected peers
 		// on error, there might be no response from the node
 		if resp.Response == nil {
@@ -92,12 +127,27 @@ func CheckCluster(ctx context.Context) *models.ClusterHealthResults {
 		}
 		// if we get a response, let's check we get the expected nodes
 		observedNodes := []string{}
-		for _, peer := range resp.Response.PodResults {
+		for podName, peer := range resp.Response.PodResults {
+			if peer.Role == RoleGateway || excusedNodes[peer.HostIP.String()] || !query.matchesResult(podName, peer) {
+				// the gateways of other clusters aren't part of this cluster's health, the unavailable
+				// and departed nodes aren't expected, and neither are the peers filtered out, which
+				// the instances not supporting the filter still report
+				continue
+			}
 			observedNodes = append(observedNodes, string(peer.HostIP))
+			if peer.HostIP == resp.HostIP {
+				// our own clock is never skewed compared to itself
+				continue
+			}
+			clockSkewObservers[peer.HostIP.String()]++
+			if isClockSkewed(peer.ClockOffsetMs) {
+				clockSkewVotes[peer.HostIP.String()]++
+			}
 		}
 		sort.Strings(observedNodes)
 		if len(observedNodes) != len(expectedNodes) {
 			output.OK = false
+			continue
 		}
 		for i, val := range observedNodes {
 			if val != expectedNodes[i] {
@@ -106,10 +156,37 @@ func CheckCluster(ctx context.Context) *models.ClusterHealthResults {
 			}
 		}
 	}
+	// 3. check that no node's clock is skewed, as seen by most of its peers
+	for node, votes := range clockSkewVotes {
+		if votes*2 > clockSkewObservers[node] {
+			output.NodesClockSkewed = append(output.NodesClockSkewed, node)
+			output.OK = false
+		}
+	}
+	sort.Strings(output.NodesClockSkewed)
+	// 4. check that every node is pinged by enough peers
+	if minPingers := getMinPingers(allPods); minPingers > 0 {
+		for node, inDegree := range output.Coverage {
+			if inDegree < minPingers && !excusedNodes[node] {
+				output.NodesUnderObserved = append(output.NodesUnderObserved, node)
+				output.OK = false
+			}
+		}
+		sort.Strings(output.NodesUnderObserved)
+	}
 	output.DurationNs = time.Since(start).Nanoseconds()
 	return &output
 }
 
+// isClockSkewed checks whether a clock offset is above the configured maximum
+func isClockSkewed(offsetMs int64) bool {
+	if GoldpingerConfig.MaxClockOffset <= 0 {
+		return false
+	}
+	offset := time.Duration(offsetMs) * time.Millisecond
+	return offset > GoldpingerConfig.MaxClockOffset || -offset > GoldpingerConfig.MaxClockOffset
+}
+
 // PingAllPodsResult holds results from pinging all nodes
 type PingAllPodsResult struct {
 	podName   string
@@ -124,58 +201,73 @@ func pickPodHostIP(podIP, hostIP string) string {
 	return podIP
 }
 
-func checkTargets() models.ProbeResults {
+// checkTargets returns the latest results of probing the external targets. When the
+// probe scheduler isn't running, the targets are all probed on the spot, until the deadline of the context
+func checkTargets(ctx context.Context) models.ProbeResults {
+	if probeSchedulerRunning.Load() {
+		return getLatestProbeResults()
+	}
+	probed := make([]models.ProbeResult, len(probeTargets))
+	wg := sync.WaitGroup{}
+	wg.Add(len(probeTargets))
+	for index, target := range probeTargets {
+		go func(index int, target ProbeTarget) {
+			defer wg.Done()
+			probed[index] = runProbe(ctx, target)
+		}(index, target)
+	}
+	wg.Wait()
+
 	results := make(map[string][]models.ProbeResult)
-	probes := []struct {
-		protocol string
-		hosts    []string
-		probeFn  func(addr string, timeout time.Duration) error
-		statFn   func(host string)
-		timeout  time.Duration
-	}{
-		{
-			protocol: "dns",
-			hosts:    GoldpingerConfig.DnsHosts,
-			probeFn:  doDNSProbe,
-			statFn:   CountDnsError,
-			timeout:  GoldpingerConfig.DnsCheckTimeout,
-		},
-		{
-			protocol: "http",
-			hosts:    GoldpingerConfig.HTTPTargets,
-			probeFn:  doHTTPProbe,
-			statFn:   CountHttpError,
-			timeout:  GoldpingerConfig.HTTPCheckTimeout,
-		},
-		{
-			protocol: "tcp",
-			hosts:    GoldpingerConfig.TCPTargets,
-			probeFn:  doTCPProbe,
-			statFn:   CountTcpError,
-			timeout:  GoldpingerConfig.TCPCheckTimeout,
-		},
+	for index, target := range probeTargets {
+		results[target.Target] = append(results[target.Target], probed[index])
 	}
+	return results
+}
 
-	for _, probe := range probes {
-		for _, host := range probe.hosts {
-			if _, ok := results[host]; !ok {
-				results[host] = []models.ProbeResult{}
-			}
+// runProbe probes a single target with its registered prober, within the probe timeout and the deadline
+// of the context, and records the outcome
+func runProbe(ctx context.Context, target ProbeTarget) models.ProbeResult {
+	res := models.ProbeResult{
+		Protocol:  target.Protocol,
+		Expect:    target.Expect,
+		ProbeTime: strfmt.DateTime(time.Now()),
+	}
+	prober, ok := getProber(target.Protocol)
+	if !ok {
+		res.Error = "no prober registered for protocol " + target.Protocol
+		return res
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, getProbeTimeout(target))
+	defer cancel()
+
+	start := time.Now()
+	err := prober.Probe(ctx, target)
+	duration := time.Since(start)
+	res.ResponseTimeMs = duration.Milliseconds()
 
-			res := models.ProbeResult{Protocol: probe.protocol}
-			start := time.Now()
-			err := probe.probeFn(host, probe.timeout)
+	if target.Expect == ExpectDeny {
+		// the target should be unreachable, so it's getting through that needs reporting, even when
+		// the target answered with an error
+		denied := isProbeDenied(err)
+		ObserveProbe(target.Protocol, target.Target, duration, denied)
+		if !denied {
+			res.Error = "connection was unexpectedly allowed"
 			if err != nil {
-				res.Error = err.Error()
-				probe.statFn(host)
+				res.Error += ": " + err.Error()
 			}
-
-			res.ResponseTimeMs = time.Since(start).Milliseconds()
-			results[host] = append(results[host], res)
+			res.Violation = true
+			CountProbeViolation(target.Protocol, target.Target)
 		}
+		return res
 	}
-
-	return results
+	ObserveProbe(target.Protocol, target.Target, duration, err == nil)
+	if err != nil {
+		res.Error = err.Error()
+		CountProbeError(target.Protocol, target.Target)
+	}
+	return res
 }
 
 // CheckServicePodsResult results of the /check operation
@@ -186,15 +278,24 @@ type CheckServicePodsResult struct {
 	podIPv4           strfmt.IPv4
 }
 
-// CheckAllPods calls all neighbours and returns a detailed report
-func CheckAllPods(checkAllCtx context.Context, pods map[string]*GoldpingerPod) *models.CheckAllResults {
+// CheckAllPods calls all neighbours and returns a detailed report. The filter of the query is passed on to
+// their /check, for them to only report on the same peers, along with the time they have to answer
+func CheckAllPods(checkAllCtx context.Context, pods map[string]*GoldpingerPod, query CheckQuery) *models.CheckAllResults {
 	result := models.CheckAllResults{Responses: make(map[string]models.CheckAllPodResult)}
 
-	ch := make(chan CheckServicePodsResult, len(pods))
+	// canaries don't run goldpinger, and gateways belong to other clusters: only call /check on peers
+	peers := make([]*GoldpingerPod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.Role == RolePeer {
+			peers = append(peers, pod)
+		}
+	}
+
+	ch := make(chan CheckServicePodsResult, len(peers))
 	wg := sync.WaitGroup{}
-	wg.Add(len(pods))
+	wg.Add(len(peers))
 
-	for _, pod := range pods {
+	for _, pod := range peers {
 		go func(pod *GoldpingerPod) {
 			// logger
 			logger := zap.L().With(
@@ -204,20 +305,33 @@ func CheckAllPods(checkAllCtx context.Context, pods map[string]*GoldpingerPod) *
 				zap.String("podIP", pod.PodIP),
 			)
 
+			// wait for a free worker, with --fanout-concurrency
+			release, err := acquireFanoutSlot(checkAllCtx)
+			if err == nil {
+				defer release()
+			}
+
 			// stats
 			CountCall("made", "check")
-			timer := GetLabeledPeersCallsTimer("check", pod.HostIP, pod.PodIP)
+			timer := GetLabeledPeersCallsTimer("check", pod.HostIP, pod.PodIP, pod.Source)
 
 			// setup
 			var channelResult CheckServicePodsResult
 			channelResult.podName = pod.Name
 			channelResult.hostIPv4.UnmarshalText([]byte(pod.HostIP))
 			channelResult.podIPv4.UnmarshalText([]byte(pod.PodIP))
-			client, err := getClient(pickPodHostIP(pod.PodIP, pod.HostIP))
+			var client *apiclient.Goldpinger
+			if err != nil {
+				logger.Warn("No fan-out worker was free for Check, --fanout-concurrency rejected the call", zap.Error(err))
+			} else {
+				client, err = getClient(pickPodHostIP(pod.PodIP, pod.HostIP), getPodPort(pod), getPeerIdentity(pod))
+				if err != nil {
+					logger.Warn("Couldn't get a client for Check", zap.Error(err))
+				}
+			}
 			OK := false
 
 			if err != nil {
-				logger.Warn("Couldn't get a client for Check", zap.Error(err))
 				channelResult.checkAllPodResult = models.CheckAllPodResult{
 					OK:     &OK,
 					PodIP:  channelResult.podIPv4,
@@ -226,13 +340,20 @@ func CheckAllPods(checkAllCtx context.Context, pods map[string]*GoldpingerPod) *
 				}
 				CountError("checkAll")
 			} else {
+				peerTimeout := query.getPeerCheckTimeout(checkAllCtx)
 				checkCtx, cancel := context.WithTimeout(
 					checkAllCtx,
-					GoldpingerConfig.CheckTimeout,
+					peerTimeout,
 				)
 				defer cancel()
 
-				params := operations.NewCheckServicePodsParamsWithContext(checkCtx)
+				params := operations.NewCheckServicePodsParamsWithContext(checkCtx).
+					WithInclude(query.Include).
+					WithExclude(query.Exclude).
+					WithFilter(query.Filter)
+				if timeoutSeconds := peerTimeout.Seconds() * peerCheckTimeoutShare; timeoutSeconds > 0 {
+					params.SetTimeoutSeconds(&timeoutSeconds)
+				}
 				resp, err := client.Operations.CheckServicePods(params)
 				OK = (err == nil)
 				if OK {
@@ -252,7 +373,7 @@ func CheckAllPods(checkAllCtx context.Context, pods map[string]*GoldpingerPod) *
 						HostIP: channelResult.hostIPv4,
 						Error:  err.Error(),
 					}
-					CountError("checkAll")
+					countPeerCallError("checkAll", err)
 				}
 			}
 
@@ -264,6 +385,7 @@ func CheckAllPods(checkAllCtx context.Context, pods map[string]*GoldpingerPod) *
 	close(ch)
 
 	for response := range ch {
+		response.checkAllPodResult.Status = getCheckAllPodStatus(response.checkAllPodResult)
 		result.Responses[response.podName] = response.checkAllPodResult
 		result.Hosts = append(result.Hosts, &models.CheckAllResultsHostsItems0{
 			PodName: response.podName,
@@ -298,12 +420,18 @@ func HealthCheck() *models.HealthCheckResults {
 	return &result
 }
 
-func getClient(hostIP string) (*apiclient.Goldpinger, error) {
+func getClient(hostIP string, port int, identity string) (*apiclient.Goldpinger, error) {
 	if hostIP == "" {
 		return nil, errors.New("Host or pod IP empty, can't make a call")
 	}
-	host := net.JoinHostPort(hostIP, strconv.Itoa(GoldpingerConfig.Port))
-	transport := httptransport.New(host, "", nil)
+	host := net.JoinHostPort(hostIP, strconv.Itoa(port))
+	var transport *httptransport.Runtime
+	if PeerTLSEnabled() {
+		transport = httptransport.NewWithClient(host, "", []string{"https"}, getPeerHTTPClient(host, identity))
+	} else {
+		transport = httptransport.New(host, "", nil)
+	}
+	transport.DefaultAuthentication = clientTokenAuth
 	client := apiclient.New(transport, strfmt.Default)
 	apiclient.Default.SetTransport(transport)
 
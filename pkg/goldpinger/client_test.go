@@ -0,0 +1,66 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCheckClusterWithMoreNodesThanExpected(t *testing.T) {
+	previousConfig := GoldpingerConfig
+	previousSources := discoverySources
+	defer func() {
+		GoldpingerConfig = previousConfig
+		discoverySources = previousSources
+	}()
+	GoldpingerConfig.CheckTimeout = time.Second
+	GoldpingerConfig.PingNumber = 0
+
+	// the peer reports two nodes it pings on top of itself, which this instance doesn't know of
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"podResults": {
+			"p1": {"OK": true, "HostIP": "127.0.0.1", "PodIP": "127.0.0.1", "role": "peer"},
+			"p2": {"OK": true, "HostIP": "127.0.0.2", "PodIP": "127.0.0.2", "role": "peer"},
+			"p3": {"OK": true, "HostIP": "127.0.0.3", "PodIP": "127.0.0.3", "role": "peer"}
+		}}`))
+	}))
+	defer server.Close()
+	_, portString, _ := net.SplitHostPort(server.Listener.Addr().String())
+	port, _ := strconv.Atoi(portString)
+	discoverySources = []DiscoverySource{{
+		Name:  "static",
+		Type:  SourceTypeStatic,
+		Role:  RolePeer,
+		Port:  port,
+		Peers: []StaticPeer{{Name: "p1", IP: "127.0.0.1"}},
+	}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result := CheckCluster(ctx, CheckQuery{})
+	if result.OK {
+		t.Error("CheckCluster() is OK with a peer reporting unexpected nodes")
+	}
+	if len(result.NodesHealthy) != 1 {
+		t.Errorf("healthy nodes = %v, want the peer", result.NodesHealthy)
+	}
+}
@@ -22,25 +22,93 @@ import (
 
 // GoldpingerConfig represents the configuration for goldpinger
 var GoldpingerConfig = struct {
-	StaticFilePath   string  `long:"static-file-path" description:"Folder for serving static files" env:"STATIC_FILE_PATH"`
-	ZapConfigPath    string  `long:"zap-config" description:"Path to zap config file" env:"ZAP_CONFIG" default:"/config/zap.json"`
-	KubeConfigPath   string  `long:"kubeconfig" description:"Path to kubeconfig file" env:"KUBECONFIG"`
-	RefreshInterval  int     `long:"refresh-interval" description:"If > 0, will create a thread and collect stats every n seconds" env:"REFRESH_INTERVAL" default:"30"`
-	JitterFactor     float64 `long:"jitter-factor" description:"The amount of jitter to add while pinging clients" env:"JITTER_FACTOR" default:"0.05"`
-	Hostname         string  `long:"hostname" description:"Hostname to use" env:"HOSTNAME"`
-	PodIP            string  `long:"pod-ip" description:"Pod IP to use" env:"POD_IP"`
-	PodName          string  `long:"pod-name" description:"The name of this pod - used to select --ping-number of pods using rendezvous hashing" env:"POD_NAME"`
-	PingNumber       uint    `long:"ping-number" description:"Number of peers to ping. A value of 0 indicates all peers should be pinged." default:"0" env:"PING_NUMBER"`
-	Port             int     `long:"client-port-override" description:"(for testing) use this port when calling other instances" env:"CLIENT_PORT_OVERRIDE"`
-	UseHostIP        bool    `long:"use-host-ip" description:"When making the calls, use host ip (defaults to pod ip)" env:"USE_HOST_IP"`
-	LabelSelector    string  `long:"label-selector" description:"label selector to use to discover goldpinger pods in the cluster" env:"LABEL_SELECTOR" default:"app=goldpinger"`
-	Namespace        *string `long:"namespace" description:"namespace to use to discover goldpinger pods in the cluster (empty for all). Defaults to discovering the namespace for the current pod" env:"NAMESPACE"`
-	DisplayNodeName  bool    `long:"display-nodename" description:"Display nodename other than podname in UI (defaults is podname)." env:"DISPLAY_NODENAME"`
+	StaticFilePath   string   `long:"static-file-path" description:"Folder for serving static files" env:"STATIC_FILE_PATH"`
+	ZapConfigPath    string   `long:"zap-config" description:"Path to zap config file" env:"ZAP_CONFIG" default:"/config/zap.json"`
+	KubeConfigPath   string   `long:"kubeconfig" description:"Path to kubeconfig file" env:"KUBECONFIG"`
+	RefreshInterval  int      `long:"refresh-interval" description:"If > 0, will create a thread and collect stats every n seconds" env:"REFRESH_INTERVAL" default:"30"`
+	JitterFactor     float64  `long:"jitter-factor" description:"The amount of jitter to add while pinging clients" env:"JITTER_FACTOR" default:"0.05"`
+	Hostname         string   `long:"hostname" description:"Hostname to use" env:"HOSTNAME"`
+	PodIP            string   `long:"pod-ip" description:"Pod IP to use" env:"POD_IP"`
+	NodeName         string   `long:"node-name" description:"The name of the node this pod runs on, reported to peers in /ping (defaults to the hostname)" env:"NODE_NAME"`
+	PodName          string   `long:"pod-name" description:"The name of this pod - used to select --ping-number of pods using rendezvous hashing" env:"POD_NAME"`
+	PingNumber       uint     `long:"ping-number" description:"Number of peers to ping. A value of 0 indicates all peers should be pinged." default:"0" env:"PING_NUMBER"`
+	PingStrategy     string   `long:"ping-strategy" description:"How to select the --ping-number peers to ping. Possible values are rendezvous, topology (at least --min-peers-per-zone peers in each zone, requires --discover-topology) and ring (every node is pinged by at least --min-pingers peers)." env:"PING_STRATEGY" default:"rendezvous"`
+	MinPeersPerZone  uint     `long:"min-peers-per-zone" description:"The minimum number of peers to ping in each zone, with the topology ping strategy" env:"MIN_PEERS_PER_ZONE" default:"1"`
+	MinPingers       uint     `long:"min-pingers" description:"The minimum number of peers that should ping each node. With the ring ping strategy, it is guaranteed; with any strategy, /cluster_health fails when a node is pinged by fewer peers." env:"MIN_PINGERS" default:"0"`
+	TopologyKey      string   `long:"topology-key" description:"The node label grouping peers into zones, with the topology ping strategy" env:"TOPOLOGY_KEY" default:"topology.kubernetes.io/zone"`
+	Port             int      `long:"client-port-override" description:"(for testing) use this port when calling other instances" env:"CLIENT_PORT_OVERRIDE"`
+	UseHostIP        bool     `long:"use-host-ip" description:"When making the calls, use host ip (defaults to pod ip)" env:"USE_HOST_IP"`
+	PingPaths        []string `long:"ping-paths" description:"The network paths to ping peers over (space delimited). Possible values are pod, host, nodeport and grpc. The first path is the primary one (defaults to pod, or host with --use-host-ip)." env:"PING_PATHS" env-delim:" "`
+	HostPort         int      `long:"host-port" description:"The hostPort to use for the host ping path (defaults to the client port)" env:"HOST_PORT"`
+	NodePort         int      `long:"node-port" description:"The NodePort of the goldpinger service, required by the nodeport ping path" env:"NODE_PORT"`
+	GRPCPort         int      `long:"grpc-port" description:"If > 0, serve the grpc.health.v1 health checking protocol on this port, and allow the grpc ping path" env:"GRPC_PORT"`
+	LabelSelector    string   `long:"label-selector" description:"label selector to use to discover goldpinger pods in the cluster" env:"LABEL_SELECTOR" default:"app=goldpinger"`
+	Namespace        *string  `long:"namespace" description:"namespace to use to discover goldpinger pods in the cluster (empty for all). Defaults to discovering the namespace for the current pod" env:"NAMESPACE"`
+	DiscoveryConfig  string   `long:"discovery-config" description:"Path to a YAML file describing several sources of pods to ping, overriding --label-selector and --namespace" env:"DISCOVERY_CONFIG"`
+	DisplayNodeName  bool     `long:"display-nodename" description:"Display nodename other than podname in UI (defaults is podname)." env:"DISPLAY_NODENAME"`
+	DiscoverTopology bool     `long:"discover-topology" description:"Look up the zone and region of the nodes the peers run on (requires the permission to list nodes)" env:"DISCOVER_TOPOLOGY"`
+	NodeLabels       []string `long:"node-labels" description:"Extra node labels to report for each peer, with --discover-topology (space delimited)" env:"NODE_LABELS" env-delim:" "`
+	ZoneMetrics      bool     `long:"zone-metrics" description:"Export a histogram of response times labeled with the source and destination zones, with --discover-topology" env:"ZONE_METRICS"`
 	KubernetesClient *kubernetes.Clientset
 
-	DnsHosts    []string `long:"host-to-resolve" description:"A host to attempt dns resolve on (space delimited)" env:"HOSTS_TO_RESOLVE" env-delim:" "`
-	TCPTargets  []string `long:"tcp-targets" description:"A list of external targets(<host>:<port> or <ip>:<port>) to attempt a TCP check on (space delimited)" env:"TCP_TARGETS" env-delim:" "`
-	HTTPTargets []string `long:"http-targets" description:"A list of external targets(<http or https>://<url>) to attempt an HTTP{S} check on. A 200 HTTP code is considered successful.(space delimited)" env:"HTTP_TARGETS" env-delim:" "`
+	DnsHosts        []string      `long:"host-to-resolve" description:"A host to attempt dns resolve on (space delimited)" env:"HOSTS_TO_RESOLVE" env-delim:" "`
+	TCPTargets      []string      `long:"tcp-targets" description:"A list of external targets(<host>:<port> or <ip>:<port>) to attempt a TCP check on (space delimited)" env:"TCP_TARGETS" env-delim:" "`
+	HTTPTargets     []string      `long:"http-targets" description:"A list of external targets(<http or https>://<url>) to attempt an HTTP{S} check on. A 200 HTTP code is considered successful.(space delimited)" env:"HTTP_TARGETS" env-delim:" "`
+	ProbeConfigPath string        `long:"probe-config" description:"Path to a YAML file describing external targets to probe, including targets expected to be denied" env:"PROBE_CONFIG"`
+	ProbeInterval   time.Duration `long:"probe-interval" description:"The default interval between two probes of an external target (defaults to the refresh interval)" env:"PROBE_INTERVAL"`
+
+	// Federation
+	ClusterName       string `long:"cluster-name" description:"The name of the cluster this instance runs in, among the federated clusters" env:"CLUSTER_NAME"`
+	FederationConfig  string `long:"federation-config" description:"Path to a YAML file listing remote clusters, to aggregate into /federation" env:"FEDERATION_CONFIG"`
+	FederationGateway bool   `long:"federation-gateway" description:"Ping the gateways of the other federated clusters (requires --federation-config and --cluster-name)" env:"FEDERATION_GATEWAY"`
+
+	// Unavailable peers
+	UnavailablePeerPolicy string   `long:"unavailable-peer-policy" description:"What to do with the peers that are terminating or not ready, or whose node is not ready, cordoned or tainted with --maintenance-taints. Possible values are ping (ping and expect them like any other peer), skip (neither ping nor expect them) and maintenance (ping them, but report them as in maintenance instead of expecting them; requires the permission to list nodes)." env:"UNAVAILABLE_PEER_POLICY" default:"ping"`
+	MaintenanceTaints     []string `long:"maintenance-taints" description:"The node taint keys marking a node as unavailable (space delimited)" env:"MAINTENANCE_TAINTS" env-delim:" " default:"ToBeDeletedByClusterAutoscaler"`
+
+	// Authentication
+	AuthModes           []string      `long:"auth-modes" description:"The authentication modes of the API, tried in order (space delimited). Possible values are token, kubernetes (TokenReview and SubjectAccessReview) and mtls. The API is open when empty." env:"AUTH_MODES" env-delim:" "`
+	AuthOpenPaths       []string      `long:"auth-open-paths" description:"The paths open to anyone with authentication, a path ending with a slash opening all the paths under it (space delimited)" env:"AUTH_OPEN_PATHS" env-delim:" " default:"/ping" default:"/healthz" default:"/readyz" default:"/metrics"`
+	AuthTokensFile      string        `long:"auth-tokens-file" description:"Path to a YAML file listing the bearer tokens accepted by the token mode, and the paths they may call" env:"AUTH_TOKENS_FILE"`
+	AuthCacheTTL        time.Duration `long:"auth-cache-ttl" description:"How long to cache the token and access reviews of the kubernetes mode" env:"AUTH_CACHE_TTL" default:"1m"`
+	AuthClientNames     []string      `long:"auth-client-names" description:"The client certificate common names allowed by the mtls mode (space delimited, any verified certificate when empty)" env:"AUTH_CLIENT_NAMES" env-delim:" "`
+	AuthClientTokenFile string        `long:"auth-client-token-file" description:"Path to a bearer token to send to the peers, such as the service account token" env:"AUTH_CLIENT_TOKEN_FILE"`
+
+	// Peer TLS
+	PeerTLSCert     string        `long:"peer-tls-cert" description:"Path to the certificate to present to the peers, to call each other over mutual TLS" env:"PEER_TLS_CERT"`
+	PeerTLSKey      string        `long:"peer-tls-key" description:"Path to the key of --peer-tls-cert" env:"PEER_TLS_KEY"`
+	PeerTLSCA       string        `long:"peer-tls-ca" description:"Path to the CA the peer certificates are issued by" env:"PEER_TLS_CA"`
+	PeerTLSIdentity string        `long:"peer-tls-identity" description:"The name the peer certificates are expected to be issued to. Possible values are pod, node and none." env:"PEER_TLS_IDENTITY" default:"pod"`
+	PeerTLSReload   time.Duration `long:"peer-tls-reload" description:"How often to check the peer certificate, key and CA files for changes (0 to never reload them)" env:"PEER_TLS_RELOAD" default:"1m"`
+
+	// Rate limiting
+	RateLimit         float64  `long:"rate-limit" description:"If > 0, the maximum number of calls per second to the --rate-limit-paths, from all clients" env:"RATE_LIMIT" default:"0"`
+	RateBurst         int      `long:"rate-burst" description:"The number of calls over --rate-limit allowed in a burst (defaults to the rate limit)" env:"RATE_BURST"`
+	ClientRateLimit   float64  `long:"client-rate-limit" description:"If > 0, the maximum number of calls per second to the --rate-limit-paths, from each client IP" env:"CLIENT_RATE_LIMIT" default:"0"`
+	ClientRateBurst   int      `long:"client-rate-burst" description:"The number of calls over --client-rate-limit allowed in a burst (defaults to the rate limit)" env:"CLIENT_RATE_BURST"`
+	RateLimitPaths    []string `long:"rate-limit-paths" description:"The paths subject to the rate limits, a path ending with a slash matching all the paths under it (space delimited)" env:"RATE_LIMIT_PATHS" env-delim:" " default:"/check_all" default:"/cluster_health" default:"/heatmap.png" default:"/zone_matrix" default:"/federation" default:"/federation/heatmap.png"`
+	FanoutConcurrency int      `long:"fanout-concurrency" description:"If > 0, the maximum number of concurrent calls to the peers made by /check_all and the endpoints built on it" env:"FANOUT_CONCURRENCY" default:"0"`
+
+	// Shutdown
+	ShutdownTimeout   time.Duration `long:"shutdown-timeout" description:"How long to wait for the pingers to stop and the departure to be announced when shutting down" env:"SHUTDOWN_TIMEOUT" default:"10s"`
+	AnnounceDeparture bool          `long:"announce-departure" description:"When shutting down, tell the other instances to report this one as departed rather than unreachable" env:"ANNOUNCE_DEPARTURE"`
+	DepartureTTL      time.Duration `long:"departure-ttl" description:"How long to report a peer as departed after it announced its departure" env:"DEPARTURE_TTL" default:"2m"`
+
+	// Readiness
+	ReadinessDiscoveryTimeout time.Duration `long:"readiness-discovery-timeout" description:"How long discovery can keep failing before /readyz reports this instance as not ready" env:"READINESS_DISCOVERY_TIMEOUT" default:"5m"`
+
+	// Stale results
+	StaleResultThreshold time.Duration `long:"stale-result-threshold" description:"How old a ping result can get before it's reported as unknown rather than ok or failed (defaults to 3 refresh intervals)" env:"STALE_RESULT_THRESHOLD"`
+
+	// Retries
+	PingRetries    int           `long:"ping-retries" description:"How many times to retry a failed ping right away, to tell a dropped packet from an outage. A peer failing all its retries is pinged once until it answers again" env:"PING_RETRIES" default:"0"`
+	PingRetryDelay time.Duration `long:"ping-retry-delay" description:"How long to wait before the first retry of a failed ping, doubling with each retry" env:"PING_RETRY_DELAY" default:"100ms"`
+
+	// Peer state
+	PeerDownAfter     int           `long:"peer-down-after" description:"How many pings in a row a peer has to fail to be reported as down rather than degraded" env:"PEER_DOWN_AFTER" default:"3"`
+	PeerUpAfter       int           `long:"peer-up-after" description:"How many pings in a row a peer that is down has to succeed to be reported as up again" env:"PEER_UP_AFTER" default:"2"`
+	PeerFlapWindow    time.Duration `long:"peer-flap-window" description:"The window over which the transitions of a peer between up and down are counted" env:"PEER_FLAP_WINDOW" default:"10m"`
+	PeerFlapThreshold int           `long:"peer-flap-threshold" description:"How many transitions between up and down within --peer-flap-window make a peer flapping, 0 to disable" env:"PEER_FLAP_THRESHOLD" default:"4"`
 
 	IPVersions []string `long:"ip-versions" description:"The IP versions to use (space delimited). Possible values are 4 and 6 (defaults to 4)." env:"IP_VERSIONS" env-delim:" "`
 
@@ -54,4 +122,12 @@ var GoldpingerConfig = struct {
 	TCPCheckTimeout   time.Duration `long:"tcp-targets-timeout" description:"The timeout for a tcp check on the provided tcp-targets" env:"TCP_TARGETS_TIMEOUT" default:"500ms"`
 	DnsCheckTimeout   time.Duration `long:"dns-targets-timeout" description:"The timeout for a dns check on the provided dns-targets" env:"DNS_TARGETS_TIMEOUT" default:"500ms"`
 	HTTPCheckTimeout  time.Duration `long:"http-targets-timeout" description:"The timeout for a http check on the provided http-targets" env:"HTTP_TARGETS_TIMEOUT" default:"500ms"`
+	ProbeTimeout      time.Duration `long:"probe-timeout" description:"The default timeout for probes of custom protocols" env:"PROBE_TIMEOUT" default:"500ms"`
+	DiscoveryTimeout  time.Duration `long:"discovery-dns-timeout" description:"The timeout for resolving the record of a dns discovery source, independent of --dns-targets-timeout" env:"DISCOVERY_DNS_TIMEOUT" default:"500ms"`
+
+	// Check queries
+	MaxCheckTimeout    time.Duration `long:"max-check-timeout" description:"The longest timeout the timeout query parameter of /check can ask for" env:"MAX_CHECK_TIMEOUT" default:"10s"`
+	MaxCheckAllTimeout time.Duration `long:"max-check-all-timeout" description:"The longest timeout the timeout query parameter of /check_all, /cluster_health and /heatmap.png can ask for" env:"MAX_CHECK_ALL_TIMEOUT" default:"60s"`
+
+	MaxClockOffset time.Duration `long:"max-clock-offset" description:"If > 0, /cluster_health fails when a node's clock is off by more than this, as seen by most of its peers" env:"MAX_CLOCK_OFFSET" default:"0"`
 }{}
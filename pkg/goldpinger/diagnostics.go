@@ -0,0 +1,154 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+	"github.com/go-openapi/strfmt"
+	"go.uber.org/zap"
+)
+
+// pingerGoroutines counts the pinger goroutines still running
+var pingerGoroutines atomic.Int64
+
+// lastResult is when the collector last received a result for a peer
+type lastResult struct {
+	time   time.Time
+	hostIP string
+	podIP  string
+}
+
+// diagnostics holds the state of the pinger pipeline reported by /debug/goldpinger
+var diagnostics = struct {
+	sync.Mutex
+	resultsChan chan PingAllPodsResult
+	lastResults map[string]lastResult
+}{lastResults: make(map[string]lastResult)}
+
+// setResultsChan records the channel the pingers send their results to
+func setResultsChan(resultsChan chan PingAllPodsResult) {
+	diagnostics.Lock()
+	defer diagnostics.Unlock()
+	diagnostics.resultsChan = resultsChan
+}
+
+// incPingerGoroutines adds delta to the number of pinger goroutines
+func incPingerGoroutines(delta int64) {
+	SetPingerGoroutines(pingerGoroutines.Add(delta))
+}
+
+// recordResult records when the collector received a result
+func recordResult(response PingAllPodsResult) {
+	diagnostics.Lock()
+	defer diagnostics.Unlock()
+	if response.deleted {
+		if last, ok := diagnostics.lastResults[response.podName]; ok {
+			DeletePeerLastResult(last.hostIP, last.podIP)
+			DeleteUnexpectedSnat(last.hostIP, last.podIP)
+			DeletePeerClockOffset(last.hostIP, last.podIP)
+			delete(diagnostics.lastResults, response.podName)
+		}
+		return
+	}
+	last := lastResult{
+		time:   time.Now(),
+		hostIP: string(response.podResult.HostIP),
+		podIP:  string(response.podResult.PodIP),
+	}
+	diagnostics.lastResults[response.podName] = last
+	SetPeerLastResult(last.hostIP, last.podIP, last.time)
+}
+
+// GetDiagnostics reports the state of the pinger pipeline, and updates the matching metrics
+func GetDiagnostics() *models.DiagnosticsResults {
+	now := time.Now()
+	threshold := getStaleThreshold()
+
+	readiness.Lock()
+	pinged := readiness.pinged
+	lastDiscovery := readiness.lastDiscovery
+	readiness.Unlock()
+
+	diagnostics.Lock()
+	resultsChan := diagnostics.resultsChan
+	peers := make(map[string]models.PeerDiagnostics, len(pinged))
+	stalePeers := []string{}
+	for podName, started := range pinged {
+		peer := models.PeerDiagnostics{PingerStarted: strfmt.DateTime(started)}
+		since := started
+		if last, ok := diagnostics.lastResults[podName]; ok {
+			peer.LastResult = strfmt.DateTime(last.time)
+			if last.time.After(since) {
+				since = last.time
+			}
+		}
+		if now.Sub(since) > threshold {
+			peer.Stale = true
+			stalePeers = append(stalePeers, podName)
+		}
+		peers[podName] = peer
+	}
+	diagnostics.Unlock()
+	sort.Strings(stalePeers)
+
+	goroutines := pingerGoroutines.Load()
+	SetPingerGoroutines(goroutines)
+	SetResultsChannelDepth(len(resultsChan))
+	SetStalePeers(len(stalePeers))
+
+	result := &models.DiagnosticsResults{
+		ActivePingers:          int64(len(pinged)),
+		GeneratedAt:            strfmt.DateTime(now),
+		Goroutines:             int64(runtime.NumGoroutine()),
+		PingerGoroutines:       goroutines,
+		Peers:                  peers,
+		ResultsChannelCapacity: int64(cap(resultsChan)),
+		ResultsChannelDepth:    int64(len(resultsChan)),
+		StalePeers:             stalePeers,
+		StaleThresholdMs:       threshold.Milliseconds(),
+	}
+	if !lastDiscovery.IsZero() {
+		result.LastDiscovery = strfmt.DateTime(lastDiscovery)
+	}
+	return result
+}
+
+// watchPipeline updates the self-diagnostics metrics every refresh interval and warns about the stale
+// peers. It runs apart from the collector, to keep reporting if the collector stalls
+func watchPipeline() {
+	ticker := time.NewTicker(time.Duration(GoldpingerConfig.RefreshInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCollector:
+			return
+		case <-ticker.C:
+			report := GetDiagnostics()
+			if len(report.StalePeers) > 0 {
+				zap.L().Warn("No recent ping results for some peers",
+					zap.Strings("stalePeers", report.StalePeers),
+					zap.Int64("resultsChannelDepth", report.ResultsChannelDepth),
+					zap.Int64("pingerGoroutines", report.PingerGoroutines),
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+func init() {
+	RegisterDiscoverer(SourceTypeStatic, DiscovererFunc(discoverStaticPeers))
+	RegisterDiscoverer(SourceTypeDNS, DiscovererFunc(discoverDNSPeers))
+}
+
+// discoverStaticPeers returns the peers listed in the config of a static source, and in its file
+func discoverStaticPeers(ctx context.Context, source DiscoverySource) (map[string]*GoldpingerPod, error) {
+	peers := source.Peers
+	if source.File != "" {
+		data, err := os.ReadFile(source.File)
+		if err != nil {
+			return nil, fmt.Errorf("could not read peers file: %w", err)
+		}
+		var filePeers StaticPeers
+		if err := yaml.UnmarshalStrict(data, &filePeers); err != nil {
+			return nil, fmt.Errorf("could not parse peers file: %w", err)
+		}
+		peers = append(peers[:len(peers):len(peers)], filePeers.Peers...)
+	}
+
+	podMap := make(map[string]*GoldpingerPod)
+	for _, peer := range peers {
+		if peer.IP == "" {
+			return nil, fmt.Errorf("peer %q has no IP", peer.Name)
+		}
+		name := peer.Name
+		if name == "" {
+			name = peer.IP
+		}
+		hostIP := peer.HostIP
+		if hostIP == "" {
+			hostIP = peer.IP
+		}
+		podMap[name] = &GoldpingerPod{
+			Name:   name,
+			PodIP:  peer.IP,
+			HostIP: hostIP,
+			Zone:   peer.Zone,
+			Region: peer.Region,
+		}
+	}
+	return podMap, nil
+}
+
+// discoverDNSPeers resolves the record of a dns source. A records give one peer per IP, named
+// after it. SRV records give one peer per target, named after it, called on the port of the record
+func discoverDNSPeers(ctx context.Context, source DiscoverySource) (map[string]*GoldpingerPod, error) {
+	ctx, cancel := context.WithTimeout(ctx, GoldpingerConfig.DiscoveryTimeout)
+	defer cancel()
+	resolver := net.Resolver{}
+
+	podMap := make(map[string]*GoldpingerPod)
+	if source.RecordType != "SRV" {
+		ips, err := resolveDNSPeer(ctx, &resolver, source.Record)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			podMap[ip] = &GoldpingerPod{Name: ip, PodIP: ip, HostIP: ip}
+		}
+		return podMap, nil
+	}
+
+	_, records, err := resolver.LookupSRV(ctx, "", "", source.Record)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		name := strings.TrimSuffix(record.Target, ".")
+		ips, err := resolveDNSPeer(ctx, &resolver, name)
+		if err != nil {
+			return nil, err
+		}
+		podMap[name] = &GoldpingerPod{Name: name, PodIP: ips[0], HostIP: ips[0], Port: int(record.Port)}
+	}
+	return podMap, nil
+}
+
+// resolveDNSPeer resolves a host name to the IPs of the configured IP version
+func resolveDNSPeer(ctx context.Context, resolver *net.Resolver, host string) ([]string, error) {
+	addrs, err := resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	ips := []string{}
+	for _, addr := range addrs {
+		if ipMatchesConfig(addr) {
+			ips = append(ips, addr)
+		}
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("%s was resolved to 0 ips of version %s", host, GoldpingerConfig.IPVersions[0])
+	}
+	return ips, nil
+}
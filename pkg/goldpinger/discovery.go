@@ -0,0 +1,254 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// RolePeer is the default role: the pods are goldpinger instances, pinged on /ping
+	RolePeer = "peer"
+	// RoleCanary is for pods of other workloads, checked with a TCP connection to their port
+	RoleCanary = "canary"
+	// RoleGateway is for the gateways of other federated clusters, pinged on /ping but left
+	// out of /check_all and /cluster_health
+	RoleGateway = "gateway"
+
+	// SourceTypeKubernetes discovers the pods matching a label selector
+	SourceTypeKubernetes = "kubernetes"
+	// SourceTypeStatic reads a list of peers from the config, or from a file
+	SourceTypeStatic = "static"
+	// SourceTypeDNS resolves the peers from DNS A/AAAA or SRV records
+	SourceTypeDNS = "dns"
+
+	// defaultSourceName is the name of the source built from --label-selector and --namespace
+	defaultSourceName = "default"
+)
+
+// DiscoverySource describes a set of pods to ping
+type DiscoverySource struct {
+	// Name identifies the source in the results and the metrics
+	Name string `json:"name"`
+	// Type is the type of a registered Discoverer: kubernetes (default), static, dns or any custom one
+	Type string `json:"type,omitempty"`
+	// Port is the port to call the pods on, defaults to the client port
+	Port int `json:"port,omitempty"`
+	// Role is either peer (default) or canary
+	Role string `json:"role,omitempty"`
+
+	// Namespace is the namespace to discover the pods in (empty for all), defaults to --namespace.
+	// Only used by the kubernetes type
+	Namespace *string `json:"namespace,omitempty"`
+	// LabelSelector selects the pods to ping. Only used by the kubernetes type
+	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// Peers is the list of peers. Only used by the static type
+	Peers []StaticPeer `json:"peers,omitempty"`
+	// File is the path to a YAML file listing the peers, read on each refresh. Only used by the static type
+	File string `json:"file,omitempty"`
+
+	// Record is the DNS name to resolve. Only used by the dns type
+	Record string `json:"record,omitempty"`
+	// RecordType is either A (default, for A and AAAA records) or SRV. Only used by the dns type
+	RecordType string `json:"recordType,omitempty"`
+}
+
+// StaticPeer describes a single peer of a static source
+type StaticPeer struct {
+	// Name is the name of the peer, defaults to its IP
+	Name string `json:"name,omitempty"`
+	// IP is the IP address to ping the peer on
+	IP string `json:"ip"`
+	// HostIP is the IP of the host of the peer, defaults to its IP
+	HostIP string `json:"hostIP,omitempty"`
+	// Zone is the zone of the peer
+	Zone string `json:"zone,omitempty"`
+	// Region is the region of the peer
+	Region string `json:"region,omitempty"`
+}
+
+// StaticPeers is the format of the file of a static source
+type StaticPeers struct {
+	Peers []StaticPeer `json:"peers"`
+}
+
+// Discoverer lists the pods of a discovery source, keyed by a name unique within the source.
+// Only the names, IPs and topology of the pods need to be filled in
+type Discoverer interface {
+	Discover(ctx context.Context, source DiscoverySource) (map[string]*GoldpingerPod, error)
+}
+
+// DiscovererFunc turns a function with the right signature into a Discoverer
+type DiscovererFunc func(ctx context.Context, source DiscoverySource) (map[string]*GoldpingerPod, error)
+
+// Discover calls the function
+func (fn DiscovererFunc) Discover(ctx context.Context, source DiscoverySource) (map[string]*GoldpingerPod, error) {
+	return fn(ctx, source)
+}
+
+// discoverers holds the registered discoverers, by source type
+var discoverers = make(map[string]Discoverer)
+
+// discoverersMux controls concurrent access to discoverers
+var discoverersMux = sync.RWMutex{}
+
+// RegisterDiscoverer makes a discoverer available to sources of the given type,
+// replacing any discoverer already registered for it.
+// It needs to be called before the discovery sources are loaded, typically from an init function
+func RegisterDiscoverer(sourceType string, discoverer Discoverer) {
+	discoverersMux.Lock()
+	defer discoverersMux.Unlock()
+	discoverers[sourceType] = discoverer
+}
+
+// getDiscoverer returns the discoverer registered for the given source type
+func getDiscoverer(sourceType string) (Discoverer, bool) {
+	discoverersMux.RLock()
+	defer discoverersMux.RUnlock()
+	discoverer, ok := discoverers[sourceType]
+	return discoverer, ok
+}
+
+// getDiscovererTypes returns the sorted list of source types with a registered discoverer
+func getDiscovererTypes() []string {
+	discoverersMux.RLock()
+	defer discoverersMux.RUnlock()
+	types := make([]string, 0, len(discoverers))
+	for sourceType := range discoverers {
+		types = append(types, sourceType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// DiscoveryConfig is the format of the file passed with --discovery-config
+type DiscoveryConfig struct {
+	Sources []DiscoverySource `json:"sources"`
+}
+
+// discoverySources holds all the sources to discover pods from, it is only written at startup
+var discoverySources []DiscoverySource
+
+// LoadDiscoverySources builds the list of sources to discover pods from, either from the
+// discovery config file or from the --label-selector and --namespace flags
+func LoadDiscoverySources() error {
+	if GoldpingerConfig.DiscoveryConfig == "" {
+		discoverySources = []DiscoverySource{{
+			Name:          defaultSourceName,
+			Type:          SourceTypeKubernetes,
+			Namespace:     GoldpingerConfig.Namespace,
+			LabelSelector: GoldpingerConfig.LabelSelector,
+			Role:          RolePeer,
+		}}
+		return nil
+	}
+
+	data, err := os.ReadFile(GoldpingerConfig.DiscoveryConfig)
+	if err != nil {
+		return fmt.Errorf("could not read discovery config: %w", err)
+	}
+	var config DiscoveryConfig
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return fmt.Errorf("could not parse discovery config: %w", err)
+	}
+	if len(config.Sources) == 0 {
+		return fmt.Errorf("no sources in discovery config")
+	}
+
+	names := make(map[string]bool)
+	for i := range config.Sources {
+		source := &config.Sources[i]
+		if source.Name == "" {
+			return fmt.Errorf("discovery source %d has no name", i)
+		}
+		if names[source.Name] {
+			return fmt.Errorf("duplicate discovery source %q", source.Name)
+		}
+		names[source.Name] = true
+		if source.Type == "" {
+			source.Type = SourceTypeKubernetes
+		}
+		if _, ok := getDiscoverer(source.Type); !ok {
+			return fmt.Errorf("unknown type %q for discovery source %q, known types are %v",
+				source.Type, source.Name, getDiscovererTypes())
+		}
+		switch source.Type {
+		case SourceTypeKubernetes:
+			if source.Namespace == nil {
+				source.Namespace = GoldpingerConfig.Namespace
+			}
+		case SourceTypeStatic:
+			if len(source.Peers) == 0 && source.File == "" {
+				return fmt.Errorf("static discovery source %q needs peers or a file", source.Name)
+			}
+		case SourceTypeDNS:
+			if source.Record == "" {
+				return fmt.Errorf("dns discovery source %q has no record", source.Name)
+			}
+			switch source.RecordType {
+			case "":
+				source.RecordType = "A"
+			case "A", "SRV":
+			default:
+				return fmt.Errorf("unknown record type %q for dns discovery source %q", source.RecordType, source.Name)
+			}
+		}
+		switch source.Role {
+		case "":
+			source.Role = RolePeer
+		case RolePeer:
+		case RoleCanary:
+			if source.Port == 0 {
+				return fmt.Errorf("discovery source %q has the %s role but no port", source.Name, source.Role)
+			}
+		default:
+			return fmt.Errorf("unknown role %q for discovery source %q", source.Role, source.Name)
+		}
+	}
+	discoverySources = config.Sources
+	if GoldpingerConfig.DiscoverTopology && !NeedsKubernetesClient() {
+		return fmt.Errorf("--discover-topology requires a kubernetes discovery source")
+	}
+	return nil
+}
+
+// NeedsKubernetesClient tells whether any discovery source, or the kubernetes authentication mode,
+// needs to talk to the kubernetes API server
+func NeedsKubernetesClient() bool {
+	if hasAuthMode(AuthModeKubernetes) {
+		return true
+	}
+	for _, source := range discoverySources {
+		if source.Type == SourceTypeKubernetes {
+			return true
+		}
+	}
+	return false
+}
+
+// getPodPort returns the port to call the given pod on
+func getPodPort(pod *GoldpingerPod) int {
+	if pod.Port != 0 {
+		return pod.Port
+	}
+	return GoldpingerConfig.Port
+}
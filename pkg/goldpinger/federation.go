@@ -0,0 +1,374 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	apiclient "github.com/bloomberg/goldpinger/v3/pkg/client"
+	"github.com/bloomberg/goldpinger/v3/pkg/client/operations"
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"go.uber.org/zap"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+)
+
+// FederationCluster describes a remote cluster running goldpinger
+type FederationCluster struct {
+	// Name identifies the cluster
+	Name string `json:"name"`
+	// Endpoint is the URL of the goldpinger service of the cluster, for example http://goldpinger.example.com:8080
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// KubeconfigContext is the context of the kubeconfig to reach the cluster's API server with,
+	// when there is no endpoint. Goldpinger is then called through the API server's service proxy
+	KubeconfigContext string `json:"kubeconfigContext,omitempty"`
+	// Kubeconfig is the path to the kubeconfig, defaults to --kubeconfig
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// Namespace is the namespace of the goldpinger service, defaults to default
+	Namespace string `json:"namespace,omitempty"`
+	// Service is the name of the goldpinger service, defaults to goldpinger
+	Service string `json:"service,omitempty"`
+	// ServicePort is the port of the goldpinger service, defaults to 8080
+	ServicePort int `json:"servicePort,omitempty"`
+
+	// Gateways are the IPs of the gateway pods of the cluster, pinged by the gateways of the other clusters
+	Gateways []string `json:"gateways,omitempty"`
+	// GatewayPort is the port to ping the gateways on, defaults to the client port
+	GatewayPort int `json:"gatewayPort,omitempty"`
+}
+
+// FederationConfig is the format of the file passed with --federation-config
+type FederationConfig struct {
+	Clusters []FederationCluster `json:"clusters"`
+}
+
+// federatedCluster is a FederationCluster with a client to call its goldpinger
+type federatedCluster struct {
+	FederationCluster
+	client *apiclient.Goldpinger
+}
+
+// federatedClusters holds the clusters of the federation, it is only written at startup
+var federatedClusters []federatedCluster
+
+// LoadFederationConfig reads the clusters of the federation from --federation-config. With
+// --federation-gateway, it also adds a discovery source for the gateways of each other cluster.
+// It needs to be called after LoadDiscoverySources
+func LoadFederationConfig() error {
+	if GoldpingerConfig.FederationConfig == "" {
+		if GoldpingerConfig.FederationGateway {
+			return errors.New("--federation-gateway requires --federation-config to be set")
+		}
+		return nil
+	}
+
+	data, err := os.ReadFile(GoldpingerConfig.FederationConfig)
+	if err != nil {
+		return fmt.Errorf("could not read federation config: %w", err)
+	}
+	var config FederationConfig
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return fmt.Errorf("could not parse federation config: %w", err)
+	}
+
+	names := make(map[string]bool)
+	clusters := make([]federatedCluster, 0, len(config.Clusters))
+	for i, cluster := range config.Clusters {
+		if cluster.Name == "" {
+			return fmt.Errorf("federated cluster %d has no name", i)
+		}
+		if names[cluster.Name] {
+			return fmt.Errorf("duplicate federated cluster %q", cluster.Name)
+		}
+		names[cluster.Name] = true
+		client, err := getFederationClient(cluster)
+		if err != nil {
+			return fmt.Errorf("could not get a client for federated cluster %q: %w", cluster.Name, err)
+		}
+		clusters = append(clusters, federatedCluster{FederationCluster: cluster, client: client})
+	}
+
+	if GoldpingerConfig.FederationGateway {
+		if !names[GoldpingerConfig.ClusterName] {
+			return fmt.Errorf("--federation-gateway requires --cluster-name to be one of the federated clusters")
+		}
+		for _, cluster := range config.Clusters {
+			if cluster.Name == GoldpingerConfig.ClusterName || len(cluster.Gateways) == 0 {
+				continue
+			}
+			for _, source := range discoverySources {
+				if source.Name == cluster.Name {
+					return fmt.Errorf("discovery source %q has the name of a federated cluster", source.Name)
+				}
+			}
+			peers := make([]StaticPeer, 0, len(cluster.Gateways))
+			for _, gateway := range cluster.Gateways {
+				peers = append(peers, StaticPeer{IP: gateway})
+			}
+			discoverySources = append(discoverySources, DiscoverySource{
+				Name:  cluster.Name,
+				Type:  SourceTypeStatic,
+				Role:  RoleGateway,
+				Port:  cluster.GatewayPort,
+				Peers: peers,
+			})
+		}
+	}
+
+	federatedClusters = clusters
+	return nil
+}
+
+// getFederationClient returns a client calling the goldpinger of a cluster, either on its endpoint
+// or through the service proxy of its API server
+func getFederationClient(cluster FederationCluster) (*apiclient.Goldpinger, error) {
+	if cluster.Endpoint != "" {
+		u, err := url.Parse(cluster.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("invalid url scheme: '%s' in endpoint", u.Scheme)
+		}
+		var transport *httptransport.Runtime
+		if u.Scheme == "https" && PeerTLSEnabled() {
+			// the endpoint may forward the calls to any instance, so only the CA can be checked
+			transport = httptransport.NewWithClient(u.Host, u.Path, []string{u.Scheme}, getPeerHTTPClient(u.Host, ""))
+		} else {
+			transport = httptransport.New(u.Host, u.Path, []string{u.Scheme})
+		}
+		transport.DefaultAuthentication = clientTokenAuth
+		return apiclient.New(transport, strfmt.Default), nil
+	}
+	if cluster.KubeconfigContext == "" {
+		return nil, errors.New("either an endpoint or a kubeconfig context is required")
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = cluster.Kubeconfig
+	if loadingRules.ExplicitPath == "" {
+		loadingRules.ExplicitPath = GoldpingerConfig.KubeConfigPath
+	}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: cluster.KubeconfigContext},
+	).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(config.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, service, port := cluster.Namespace, cluster.Service, cluster.ServicePort
+	if namespace == "" {
+		namespace = "default"
+	}
+	if service == "" {
+		service = "goldpinger"
+	}
+	if port == 0 {
+		port = 8080
+	}
+	basePath := u.Path + "/api/v1/namespaces/" + namespace + "/services/" + service + ":" + strconv.Itoa(port) + "/proxy"
+	transport := httptransport.NewWithClient(u.Host, basePath, []string{u.Scheme}, httpClient)
+	return apiclient.New(transport, strfmt.Default), nil
+}
+
+// federatedClusterResult holds the results of calling the goldpinger of a cluster
+type federatedClusterResult struct {
+	name     string
+	health   *models.ClusterHealthResults
+	checkAll *models.CheckAllResults
+	err      error
+}
+
+// checkFederatedClusters calls /check_all, and /cluster_health if asked to, on all the federated clusters
+func checkFederatedClusters(ctx context.Context, withHealth bool) []federatedClusterResult {
+	results := make([]federatedClusterResult, len(federatedClusters))
+	wg := sync.WaitGroup{}
+	wg.Add(len(federatedClusters))
+	for i, cluster := range federatedClusters {
+		go func(i int, cluster federatedCluster) {
+			defer wg.Done()
+			CountCall("made", "federation")
+			results[i] = checkFederatedCluster(ctx, cluster, withHealth)
+			if results[i].err != nil {
+				zap.L().Warn("Error checking federated cluster", zap.String("cluster", cluster.Name), zap.Error(results[i].err))
+				CountError("federation")
+			}
+		}(i, cluster)
+	}
+	wg.Wait()
+	return results
+}
+
+// checkFederatedCluster calls /check_all, and /cluster_health if asked to, on a federated cluster at the same
+// time, asking it to answer within what is left of the deadline of the context
+func checkFederatedCluster(ctx context.Context, cluster federatedCluster, withHealth bool) federatedClusterResult {
+	result := federatedClusterResult{name: cluster.Name}
+	var timeoutSeconds *float64
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout := time.Until(deadline).Seconds() * peerCheckTimeoutShare
+		timeoutSeconds = &timeout
+	}
+
+	var healthErr, checkAllErr error
+	wg := sync.WaitGroup{}
+	if withHealth {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			params := operations.NewClusterHealthParamsWithContext(ctx).WithTimeoutSeconds(timeoutSeconds)
+			resp, err := cluster.client.Operations.ClusterHealth(params)
+			var unhealthy *operations.ClusterHealthIMATeapot
+			switch {
+			case err == nil:
+				result.health = resp.Payload
+			case errors.As(err, &unhealthy):
+				// an unhealthy cluster still returns its results
+				result.health = unhealthy.Payload
+			default:
+				healthErr = fmt.Errorf("cluster_health: %w", err)
+			}
+		}()
+	}
+	params := operations.NewCheckAllPodsParamsWithContext(ctx).WithTimeoutSeconds(timeoutSeconds)
+	resp, err := cluster.client.Operations.CheckAllPods(params)
+	if err != nil {
+		checkAllErr = fmt.Errorf("check_all: %w", err)
+	} else {
+		result.checkAll = resp.Payload
+	}
+	wg.Wait()
+
+	result.err = errors.Join(healthErr, checkAllErr)
+	return result
+}
+
+// CheckFederation aggregates the health of all the federated clusters, and the latency and loss
+// between their gateways
+func CheckFederation(ctx context.Context) *models.FederationResults {
+	start := time.Now()
+	OK := true
+	output := models.FederationResults{
+		GeneratedAt: strfmt.DateTime(start),
+		OK:          &OK,
+		Clusters:    make(map[string]models.FederationClusterResult),
+	}
+
+	links := make(map[zoneLink]*zoneLinkStats)
+	for _, result := range checkFederatedClusters(ctx, true) {
+		clusterOK := result.err == nil && result.health != nil && result.health.OK
+		clusterResult := models.FederationClusterResult{
+			OK:     &clusterOK,
+			Health: result.health,
+		}
+		if result.err != nil {
+			clusterResult.Error = result.err.Error()
+		}
+		output.Clusters[result.name] = clusterResult
+		OK = OK && clusterOK
+
+		if result.checkAll == nil {
+			continue
+		}
+		// the gateways report the peers of other clusters under a source named after the cluster
+		for _, resp := range result.checkAll.Responses {
+			if resp.Response == nil {
+				continue
+			}
+			for _, peer := range resp.Response.PodResults {
+				if peer.Role == RoleGateway && peer.Source != result.name {
+					addZoneLinkResult(links, zoneLink{result.name, peer.Source}, peer)
+				}
+			}
+		}
+	}
+	_, output.Links = getZoneLinks(links)
+	output.DurationNs = time.Since(start).Nanoseconds()
+	return &output
+}
+
+// getFederationCheckAll merges the /check_all results of all the federated clusters, prefixing
+// the pods with the name of their cluster. The gateways of other clusters are mapped to their pods
+func getFederationCheckAll(ctx context.Context) *models.CheckAllResults {
+	results := checkFederatedClusters(ctx, false)
+
+	// index the pods of each cluster by IP, to find the gateways
+	podsByIP := make(map[string]map[string]string)
+	for _, result := range results {
+		podsByIP[result.name] = make(map[string]string)
+		if result.checkAll == nil {
+			continue
+		}
+		for podName, resp := range result.checkAll.Responses {
+			podsByIP[result.name][resp.PodIP.String()] = result.name + "/" + podName
+		}
+	}
+
+	merged := models.CheckAllResults{Responses: make(map[string]models.CheckAllPodResult)}
+	for _, result := range results {
+		if result.checkAll == nil {
+			continue
+		}
+		for podName, resp := range result.checkAll.Responses {
+			if resp.OK == nil || !*resp.OK || resp.Response == nil {
+				continue
+			}
+			podResults := make(map[string]models.PodResult)
+			for peerName, peer := range resp.Response.PodResults {
+				if peer.Role == RoleGateway {
+					if name, ok := podsByIP[peer.Source][peer.PodIP.String()]; ok {
+						podResults[name] = peer
+					}
+					continue
+				}
+				podResults[result.name+"/"+peerName] = peer
+			}
+			resp.Response = &models.CheckResults{PodResults: podResults}
+			merged.Responses[result.name+"/"+podName] = resp
+		}
+	}
+	return &merged
+}
+
+// FederationHeatmapHandler returns a PNG with a heatmap representation of all the federated clusters
+func FederationHeatmapHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(
+		r.Context(),
+		GoldpingerConfig.CheckAllTimeout,
+	)
+	defer cancel()
+
+	writeHeatmap(w, r, getFederationCheckAll(ctx))
+}
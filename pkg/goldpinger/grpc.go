@@ -0,0 +1,263 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http/httptrace"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// grpcService is the service name our health server answers for, on top of the overall server health
+const grpcService = "goldpinger"
+
+// grpcMaxReconnectDelay caps the backoff between the attempts to reconnect to a gRPC target, so that
+// a target coming back is pinged again at the next refresh
+const grpcMaxReconnectDelay = time.Second
+
+// grpcServer and grpcHealthServer are the server started with --grpc-port, if any
+var (
+	grpcServer       *grpc.Server
+	grpcHealthServer *health.Server
+)
+
+func init() {
+	RegisterProber("grpc", ProberFunc(doGRPCProbe))
+}
+
+// grpcClient is a connection to a gRPC server implementing grpc.health.v1
+type grpcClient struct {
+	target string
+	conn   *grpc.ClientConn
+	// the last error connecting to the target: gRPC only reports it as the message of the failed calls
+	connectErr atomic.Pointer[error]
+}
+
+// newGRPCClient returns a client for the gRPC server at the given address. It connects lazily
+func newGRPCClient(target string, creds credentials.TransportCredentials) (*grpcClient, error) {
+	client := &grpcClient{target: target}
+	dialer := net.Dialer{}
+	conn, err := grpc.NewClient(target,
+		grpc.WithTransportCredentials(&connectErrRecorder{TransportCredentials: creds, client: client}),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, "tcp", addr)
+			client.recordConnectErr(err)
+			return conn, err
+		}),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  100 * time.Millisecond,
+				Multiplier: backoff.DefaultConfig.Multiplier,
+				Jitter:     backoff.DefaultConfig.Jitter,
+				MaxDelay:   grpcMaxReconnectDelay,
+			},
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	client.conn = conn
+	return client, nil
+}
+
+func (c *grpcClient) recordConnectErr(err error) {
+	if err == nil {
+		c.connectErr.Store(nil)
+	} else {
+		c.connectErr.Store(&err)
+	}
+}
+
+// check calls grpc.health.v1.Health/Check for the given service
+func (c *grpcClient) check(ctx context.Context, service string) error {
+	resp, err := grpc_health_v1.NewHealthClient(c.conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: service})
+	connected := c.conn.GetState() == connectivity.Ready
+	if connected {
+		// gRPC doesn't go through net/http, so tell the connect trace of the call, if any
+		if trace := httptrace.ContextClientTrace(ctx); trace != nil && trace.GotConn != nil {
+			trace.GotConn(httptrace.GotConnInfo{})
+		}
+	}
+	if err != nil {
+		switch status.Code(err) {
+		case codes.DeadlineExceeded:
+			return fmt.Errorf("%w: %s", context.DeadlineExceeded, status.Convert(err).Message())
+		case codes.Unavailable:
+			// surface why we couldn't connect, to tell it apart from an error answered by the target
+			if connectErr := c.connectErr.Load(); !connected && connectErr != nil {
+				return *connectErr
+			}
+		}
+		return err
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("%s is not serving (status %s)", c.target, resp.Status)
+	}
+	return nil
+}
+
+// close closes the connection to the target
+func (c *grpcClient) close() {
+	if err := c.conn.Close(); err != nil {
+		zap.L().Debug("Error closing gRPC connection", zap.String("target", c.target), zap.Error(err))
+	}
+}
+
+// connectErrRecorder records the TLS handshake errors of a gRPC client
+type connectErrRecorder struct {
+	credentials.TransportCredentials
+	client *grpcClient
+}
+
+func (r *connectErrRecorder) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, authInfo, err := r.TransportCredentials.ClientHandshake(ctx, authority, rawConn)
+	r.client.recordConnectErr(err)
+	return conn, authInfo, err
+}
+
+func (r *connectErrRecorder) Clone() credentials.TransportCredentials {
+	return &connectErrRecorder{TransportCredentials: r.TransportCredentials.Clone(), client: r.client}
+}
+
+// getPeerGRPCCredentials returns the credentials to ping a peer over gRPC: mutual TLS with
+// peer TLS, plain text otherwise
+func getPeerGRPCCredentials(identity string) credentials.TransportCredentials {
+	if !PeerTLSEnabled() {
+		return insecure.NewCredentials()
+	}
+	return credentials.NewTLS(newPeerTLSConfig(identity))
+}
+
+// grpcProbeClients caches the gRPC clients of the probes, by target and options
+var grpcProbeClients = struct {
+	sync.Mutex
+	clients map[string]*grpcClient
+}{clients: make(map[string]*grpcClient)}
+
+// getGRPCProbeClient returns the cached gRPC client of a probe
+func getGRPCProbeClient(target ProbeTarget) (*grpcClient, error) {
+	useTLS := target.Options["tls"] == "true"
+	serverName := target.Options["serverName"]
+	insecureSkipVerify := target.Options["insecureSkipVerify"] == "true"
+
+	grpcProbeClients.Lock()
+	defer grpcProbeClients.Unlock()
+	key := fmt.Sprintf("%s/%t/%s/%t", target.Target, useTLS, serverName, insecureSkipVerify)
+	client, ok := grpcProbeClients.clients[key]
+	if ok {
+		return client, nil
+	}
+	creds := insecure.NewCredentials()
+	if useTLS {
+		creds = credentials.NewTLS(&tls.Config{
+			ServerName:         serverName,
+			InsecureSkipVerify: insecureSkipVerify,
+		})
+	}
+	client, err := newGRPCClient(target.Target, creds)
+	if err != nil {
+		return nil, err
+	}
+	grpcProbeClients.clients[key] = client
+	return client, nil
+}
+
+// doGRPCProbe checks a gRPC server implementing grpc.health.v1. It supports the options:
+// - service: the name of the service to check (defaults to the overall server health)
+// - tls: "true" to connect over TLS
+// - insecureSkipVerify: "true" to skip the verification of the server certificate
+// - serverName: the name to verify the server certificate against
+func doGRPCProbe(ctx context.Context, target ProbeTarget) error {
+	client, err := getGRPCProbeClient(target)
+	if err != nil {
+		return err
+	}
+	return client.check(ctx, target.Options["service"])
+}
+
+// countGRPCCalls counts the gRPC calls received
+func countGRPCCalls(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	CountCall("received", "grpc_health")
+	return handler(ctx, req)
+}
+
+// StartGRPCServer starts serving the grpc.health.v1 health checking protocol on --grpc-port, so that
+// peers can ping this instance over gRPC. It's served over mutual TLS with peer TLS, in plain text otherwise
+func StartGRPCServer() error {
+	if GoldpingerConfig.GRPCPort == 0 {
+		return nil
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(GoldpingerConfig.GRPCPort)))
+	if err != nil {
+		return fmt.Errorf("could not listen for gRPC: %w", err)
+	}
+
+	options := []grpc.ServerOption{grpc.UnaryInterceptor(countGRPCCalls)}
+	if PeerTLSEnabled() {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, NextProtos: []string{"h2"}}
+		ConfigurePeerTLSServer(tlsConfig)
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	grpcServer = grpc.NewServer(options...)
+	grpcHealthServer = health.NewServer()
+	grpcHealthServer.SetServingStatus(grpcService, grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(grpcServer, grpcHealthServer)
+
+	zap.L().Info("Serving gRPC health checks", zap.String("address", listener.Addr().String()), zap.Bool("tls", PeerTLSEnabled()))
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			zap.L().Fatal("Error serving gRPC", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// stopGRPCServer gracefully stops the server started with --grpc-port, if any, and stops it
+// right away if that takes longer than the context allows
+func stopGRPCServer(ctx context.Context) error {
+	if grpcServer == nil {
+		return nil
+	}
+	grpcHealthServer.Shutdown()
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		grpcServer.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestDoGRPCProbe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(grpcService, grpc_health_v1.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("draining", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+
+	tests := []struct {
+		name       string
+		target     string
+		service    string
+		wantOK     bool
+		wantDenied bool
+	}{
+		{"overall health", listener.Addr().String(), "", true, false},
+		{"goldpinger service", listener.Addr().String(), grpcService, true, false},
+		{"service not serving", listener.Addr().String(), "draining", false, false},
+		{"unknown service", listener.Addr().String(), "unknown", false, false},
+		{"connection refused", closed.Addr().String(), "", false, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			target := ProbeTarget{Target: test.target, Options: map[string]string{"service": test.service}}
+			err := doGRPCProbe(ctx, target)
+			if (err == nil) != test.wantOK {
+				t.Errorf("doGRPCProbe() = %v, want ok %t", err, test.wantOK)
+			}
+			if denied := isProbeDenied(err); denied != test.wantDenied {
+				t.Errorf("isProbeDenied(%v) = %t, want %t", err, denied, test.wantDenied)
+			}
+		})
+	}
+
+	client, err := getGRPCProbeClient(ProbeTarget{Target: listener.Addr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached, _ := getGRPCProbeClient(ProbeTarget{Target: listener.Addr().String()}); cached != client {
+		t.Error("the gRPC client of a probe isn't reused")
+	}
+	if other, _ := getGRPCProbeClient(ProbeTarget{Target: listener.Addr().String(), Options: map[string]string{"tls": "true"}}); other == client {
+		t.Error("the gRPC client of a probe is reused with other TLS options")
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"sort"
 	"strconv"
 
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
 	"go.uber.org/zap"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
@@ -38,7 +39,7 @@ func addLabel(img *image.RGBA, x, y int, text string) {
 		Dst:  img,
 		Src:  image.NewUniform(color.RGBA{25, 200, 25, 255}),
 		Face: basicfont.Face7x13,
-		Dot:  fixed.Point26_6{fixed.Int26_6(x * 64), fixed.Int26_6(y * 64)},
+		Dot:  fixed.Point26_6{X: fixed.Int26_6(x * 64), Y: fixed.Int26_6(y * 64)},
 	}
 	drawer.DrawString(text)
 }
@@ -75,19 +76,22 @@ func getPingBoxCoordinates(col, row, boxSize, padding int) (int, int) {
 }
 
 // HeatmapHandler returns a PNG with a heatmap representation
-func HeatmapHandler(w http.ResponseWriter, r *http.Request) {
-
-	// parse the query to set the parameters
-	query := r.URL.Query()
-
+func HeatmapHandler(w http.ResponseWriter, r *http.Request, query CheckQuery) {
 	ctx, cancel := context.WithTimeout(
 		r.Context(),
-		GoldpingerConfig.CheckAllTimeout,
+		query.GetTimeout(GoldpingerConfig.CheckAllTimeout, GoldpingerConfig.MaxCheckAllTimeout),
 	)
 	defer cancel()
 
 	// get the results
-	checkResults := CheckAllPods(ctx, GetAllPods())
+	writeHeatmap(w, r, CheckAllPods(ctx, query.filterPods(GetAllPods()), query))
+}
+
+// writeHeatmap draws the given results as a PNG heatmap
+func writeHeatmap(w http.ResponseWriter, r *http.Request, checkResults *models.CheckAllResults) {
+
+	// parse the query to set the parameters
+	query := r.URL.Query()
 
 	// set some sizes
 	numberOfPods := len(checkResults.Responses)
@@ -123,6 +127,10 @@ func HeatmapHandler(w http.ResponseWriter, r *http.Request) {
 	for sourceIP, results := range checkResults.Responses {
 		if *results.OK {
 			for destinationIP, response := range results.Response.PodResults {
+				if _, ok := order[destinationIP]; !ok {
+					// only draw the peers we have a row for
+					continue
+				}
 				x, y := getPingBoxCoordinates(order[sourceIP], order[destinationIP], boxSize, paddingSize)
 				color := getPingBoxColor(response.ResponseTimeMs, tresholdLatencies)
 				drawPingBox(canvas, boxSize+x, boxSize+y, boxSize, color)
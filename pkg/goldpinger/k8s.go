@@ -16,6 +16,7 @@ package goldpinger
 
 import (
 	"context"
+	"fmt"
 	"io/ioutil"
 
 	"go.uber.org/zap"
@@ -31,9 +32,18 @@ var PodNamespace = getPodNamespace()
 
 // GoldpingerPod contains just the basic info needed to ping and keep track of a given goldpinger pod
 type GoldpingerPod struct {
-	Name   string // Name is the name of the pod
-	PodIP  string // PodIP is the IP address of the pod
-	HostIP string // HostIP is the IP address of the host where the pod lives
+	Name        string            // Name is the name of the pod
+	PodName     string            // PodName is the name of the pod, even when Name is the name of its node
+	PodIP       string            // PodIP is the IP address of the pod
+	HostIP      string            // HostIP is the IP address of the host where the pod lives
+	NodeName    string            // NodeName is the name of the node where the pod lives
+	Zone        string            // Zone is the zone of the node, with --discover-topology
+	Region      string            // Region is the region of the node, with --discover-topology
+	NodeLabels  map[string]string // NodeLabels are the node labels selected with --node-labels
+	Source      string            // Source is the name of the discovery source the pod comes from
+	Role        string            // Role is the role of the discovery source, peer or canary
+	Port        int               // Port is the port to call the pod on, if not the client port
+	Maintenance string            // Maintenance is why the pod or its node is unavailable, if it is
 }
 
 func getPodNamespace() string {
@@ -103,31 +113,113 @@ func getPodNodeName(p v1.Pod) string {
 	return p.Name
 }
 
-// GetAllPods returns a mapping from a pod name to a pointer to a GoldpingerPod(s)
-func GetAllPods() map[string]*GoldpingerPod {
+func init() {
+	RegisterDiscoverer(SourceTypeKubernetes, DiscovererFunc(discoverKubernetesPods))
+}
+
+// discoverKubernetesPods lists the running pods matching the label selector of a discovery source.
+// With several discovery sources, the pods are keyed by <namespace>/<name>
+func discoverKubernetesPods(ctx context.Context, source DiscoverySource) (map[string]*GoldpingerPod, error) {
 	timer := GetLabeledKubernetesCallsTimer()
 	listOpts := metav1.ListOptions{
 		ResourceVersion: "0",
 
-		LabelSelector: GoldpingerConfig.LabelSelector,
+		LabelSelector: source.LabelSelector,
 		FieldSelector: "status.phase=Running", // only select Running pods, otherwise we will get them before they have IPs
 	}
-	pods, err := GoldpingerConfig.KubernetesClient.CoreV1().Pods(*GoldpingerConfig.Namespace).List(context.TODO(), listOpts)
+	namespace := ""
+	if source.Namespace != nil {
+		namespace = *source.Namespace
+	}
+	pods, err := GoldpingerConfig.KubernetesClient.CoreV1().Pods(namespace).List(ctx, listOpts)
 	if err != nil {
-		zap.L().Error("Error getting pods for selector", zap.String("selector", GoldpingerConfig.LabelSelector), zap.Error(err))
 		CountError("kubernetes_api")
-	} else {
-		timer.ObserveDuration()
+		return nil, err
 	}
+	timer.ObserveDuration()
 
 	podMap := make(map[string]*GoldpingerPod)
 	for _, pod := range pods.Items {
-		podMap[pod.Name] = &GoldpingerPod{
-			Name:   getPodNodeName(pod),
-			PodIP:  getPodIP(pod),
-			HostIP: getHostIP(pod),
+		key := pod.Name
+		if len(discoverySources) > 1 {
+			key = pod.Namespace + "/" + pod.Name
+		}
+		podMap[key] = &GoldpingerPod{
+			Name:        getPodNodeName(pod),
+			PodName:     pod.Name,
+			PodIP:       getPodIP(pod),
+			HostIP:      getHostIP(pod),
+			NodeName:    pod.Spec.NodeName,
+			Maintenance: getPodMaintenance(pod),
 		}
 	}
+	return podMap, nil
+}
+
+// GetAllPods returns a mapping from a pod name to a pointer to a GoldpingerPod(s), from all the
+// discovery sources. A pod matching several sources only shows up as part of the first one
+func GetAllPods() map[string]*GoldpingerPod {
+	var nodes []v1.Node
+	if needsNodes() && GoldpingerConfig.KubernetesClient != nil {
+		var err error
+		nodes, err = listNodes()
+		recordDiscovery(nodesDiscovery, err)
+	}
+	var topology map[string]nodeTopology
+	if GoldpingerConfig.DiscoverTopology {
+		topology = getNodesTopology(nodes)
+	}
+	nodesMaintenance := getNodesMaintenance(nodes)
+
+	localNodeName := GoldpingerConfig.NodeName
+	podMap := make(map[string]*GoldpingerPod)
+	for _, source := range discoverySources {
+		discoverer, ok := getDiscoverer(source.Type)
+		if !ok {
+			recordDiscovery(source.Name, fmt.Errorf("no discoverer for the %s type", source.Type))
+			continue
+		}
+		pods, err := discoverer.Discover(context.TODO(), source)
+		if err != nil {
+			zap.L().Error("Error discovering pods",
+				zap.String("source", source.Name),
+				zap.String("type", source.Type),
+				zap.Error(err),
+			)
+			CountError("discovery")
+			recordDiscovery(source.Name, err)
+			continue
+		}
+		recordDiscovery(source.Name, nil)
+		for key, pod := range pods {
+			if _, ok := podMap[key]; ok {
+				continue
+			}
+			pod.Source = source.Name
+			pod.Role = source.Role
+			if pod.Port == 0 {
+				pod.Port = source.Port
+			}
+			if nodeTopology, ok := topology[pod.NodeName]; ok && pod.NodeName != "" {
+				pod.Zone = nodeTopology.Zone
+				pod.Region = nodeTopology.Region
+				pod.NodeLabels = nodeTopology.Labels
+			}
+			if reason, ok := nodesMaintenance[pod.NodeName]; ok && pod.Maintenance == "" && pod.NodeName != "" {
+				pod.Maintenance = reason
+			}
+			if localNodeName == "" && source.Role == RolePeer && (key == getLocalPodKey() || key == GoldpingerConfig.Hostname) {
+				localNodeName = pod.NodeName
+			}
+			if pod.Maintenance != "" && GoldpingerConfig.UnavailablePeerPolicy == UnavailablePeerPolicySkip {
+				continue
+			}
+			podMap[key] = pod
+		}
+	}
+	if nodeTopology, ok := topology[localNodeName]; ok {
+		setLocalTopology(nodeTopology)
+	}
 	return podMap
 }
 
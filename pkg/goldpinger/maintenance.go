@@ -0,0 +1,107 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// UnavailablePeerPolicyPing pings the unavailable peers and expects them to be healthy, like any other peer
+	UnavailablePeerPolicyPing = "ping"
+	// UnavailablePeerPolicySkip neither pings nor expects the unavailable peers
+	UnavailablePeerPolicySkip = "skip"
+	// UnavailablePeerPolicyMaintenance pings the unavailable peers, but reports them as in maintenance
+	// instead of expecting them to be healthy
+	UnavailablePeerPolicyMaintenance = "maintenance"
+)
+
+// The reasons why a peer is unavailable
+const (
+	MaintenancePodTerminating    = "pod-terminating"
+	MaintenancePodNotReady       = "pod-not-ready"
+	MaintenanceNodeNotReady      = "node-not-ready"
+	MaintenanceNodeUnschedulable = "node-unschedulable"
+	MaintenanceNodeTainted       = "node-tainted"
+)
+
+// ValidateUnavailablePeerPolicy checks the --unavailable-peer-policy setting
+func ValidateUnavailablePeerPolicy() error {
+	switch GoldpingerConfig.UnavailablePeerPolicy {
+	case UnavailablePeerPolicyPing, UnavailablePeerPolicySkip, UnavailablePeerPolicyMaintenance:
+		return nil
+	default:
+		return fmt.Errorf("unknown unavailable peer policy %q, must be one of %s, %s or %s",
+			GoldpingerConfig.UnavailablePeerPolicy,
+			UnavailablePeerPolicyPing, UnavailablePeerPolicySkip, UnavailablePeerPolicyMaintenance)
+	}
+}
+
+// needsNodes tells whether discovery has to list the nodes of the cluster
+func needsNodes() bool {
+	return GoldpingerConfig.DiscoverTopology || GoldpingerConfig.UnavailablePeerPolicy != UnavailablePeerPolicyPing
+}
+
+// inMaintenance tells whether a peer is unavailable and reported as in maintenance rather than expected
+func inMaintenance(pod *GoldpingerPod) bool {
+	return pod.Maintenance != "" && GoldpingerConfig.UnavailablePeerPolicy == UnavailablePeerPolicyMaintenance
+}
+
+// getPodMaintenance returns why a pod is unavailable, or an empty string if it is available
+func getPodMaintenance(pod v1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return MaintenancePodTerminating
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady && condition.Status != v1.ConditionTrue {
+			return MaintenancePodNotReady
+		}
+	}
+	return ""
+}
+
+// getNodeMaintenance returns why a node is unavailable, or an empty string if it is available
+func getNodeMaintenance(node v1.Node) string {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady && condition.Status != v1.ConditionTrue {
+			return MaintenanceNodeNotReady
+		}
+	}
+	if node.Spec.Unschedulable {
+		return MaintenanceNodeUnschedulable
+	}
+	for _, taint := range node.Spec.Taints {
+		for _, key := range GoldpingerConfig.MaintenanceTaints {
+			if taint.Key == key {
+				return MaintenanceNodeTainted + ":" + key
+			}
+		}
+	}
+	return ""
+}
+
+// getNodesMaintenance returns a mapping from node name to the reason why it is unavailable,
+// for the unavailable nodes
+func getNodesMaintenance(nodes []v1.Node) map[string]string {
+	maintenance := make(map[string]string)
+	for _, node := range nodes {
+		if reason := getNodeMaintenance(node); reason != "" {
+			maintenance[node.Name] = reason
+		}
+	}
+	return maintenance
+}
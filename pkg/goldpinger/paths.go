@@ -0,0 +1,87 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"fmt"
+)
+
+const (
+	// PingPathPod calls the peer on its pod IP, exercising the CNI
+	PingPathPod = "pod"
+	// PingPathHost calls the peer on its host IP and hostPort, exercising the node network
+	PingPathHost = "host"
+	// PingPathNodePort calls the goldpinger service NodePort on the peer's host IP, exercising kube-proxy
+	PingPathNodePort = "nodeport"
+	// PingPathGRPC health checks the peer over gRPC on its pod IP, exercising HTTP/2 handling
+	PingPathGRPC = "grpc"
+)
+
+// getPingPaths returns the list of paths to ping peers over. The first path is the primary one,
+// which is also used for the top-level fields of the results
+func getPingPaths() []string {
+	if len(GoldpingerConfig.PingPaths) > 0 {
+		return GoldpingerConfig.PingPaths
+	}
+	if GoldpingerConfig.UseHostIP {
+		return []string{PingPathHost}
+	}
+	return []string{PingPathPod}
+}
+
+// ValidatePingPaths checks that all the configured ping paths are known and usable
+func ValidatePingPaths() error {
+	for _, path := range getPingPaths() {
+		switch path {
+		case PingPathPod, PingPathHost:
+		case PingPathNodePort:
+			if GoldpingerConfig.NodePort == 0 {
+				return fmt.Errorf("the %s ping path requires --node-port to be set", path)
+			}
+		case PingPathGRPC:
+			if GoldpingerConfig.GRPCPort == 0 {
+				return fmt.Errorf("the %s ping path requires --grpc-port to be set", path)
+			}
+		default:
+			return fmt.Errorf("unknown ping path: %q", path)
+		}
+	}
+	return nil
+}
+
+// getPathAddress returns the IP and port to call to reach the given pod over the given path
+func getPathAddress(pod *GoldpingerPod, path string) (string, int) {
+	switch path {
+	case PingPathHost:
+		if GoldpingerConfig.HostPort != 0 && pod.Port == 0 {
+			return pod.HostIP, GoldpingerConfig.HostPort
+		}
+		return pod.HostIP, getPodPort(pod)
+	case PingPathNodePort:
+		return pod.HostIP, GoldpingerConfig.NodePort
+	case PingPathGRPC:
+		return pod.PodIP, GoldpingerConfig.GRPCPort
+	default:
+		return pod.PodIP, getPodPort(pod)
+	}
+}
+
+// getPathCallType returns the call type used in metrics for pings made over the given path
+func getPathCallType(path string, primary bool) string {
+	if primary {
+		return "ping"
+	}
+	return "ping_" + path
+}
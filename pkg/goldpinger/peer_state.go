@@ -0,0 +1,92 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"time"
+
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+)
+
+// peerStates are all the states a peer can be in
+var peerStates = []string{
+	models.PodResultStateUp,
+	models.PodResultStateDegraded,
+	models.PodResultStateDown,
+	models.PodResultStateFlapping,
+}
+
+// peerState tracks the pings a peer failed and succeeded in a row, and its transitions between up and down.
+// It belongs to the goroutine of the pinger of the peer
+type peerState struct {
+	// up or down, with the hysteresis of --peer-down-after and --peer-up-after; empty until the peer
+	// first succeeds or goes down
+	stable      string
+	failures    int64
+	successes   int64
+	transitions []time.Time
+}
+
+// update records the outcome of a ping, and fills in the state of the peer in its result
+func (s *peerState) update(result *models.PodResult, ok bool, now time.Time) {
+	previous := s.stable
+	if ok {
+		s.successes++
+		s.failures = 0
+		if s.stable == "" || (s.stable == models.PodResultStateDown && s.successes >= int64(GoldpingerConfig.PeerUpAfter)) {
+			s.stable = models.PodResultStateUp
+		}
+	} else {
+		s.failures++
+		s.successes = 0
+		if s.failures >= int64(GoldpingerConfig.PeerDownAfter) {
+			s.stable = models.PodResultStateDown
+		}
+	}
+	if previous != "" && previous != s.stable {
+		s.transitions = append(s.transitions, now)
+	}
+	// only keep the transitions within the flap window
+	kept := s.transitions[:0]
+	for _, transition := range s.transitions {
+		if now.Sub(transition) <= GoldpingerConfig.PeerFlapWindow {
+			kept = append(kept, transition)
+		}
+	}
+	s.transitions = kept
+
+	switch {
+	case GoldpingerConfig.PeerFlapThreshold > 0 && len(s.transitions) >= GoldpingerConfig.PeerFlapThreshold:
+		result.State = models.PodResultStateFlapping
+	case s.stable == models.PodResultStateDown:
+		result.State = models.PodResultStateDown
+	case s.failures > 0:
+		result.State = models.PodResultStateDegraded
+	default:
+		result.State = models.PodResultStateUp
+	}
+	result.ConsecutiveFailures = s.failures
+	result.ConsecutiveSuccesses = s.successes
+	result.Transitions = int64(len(s.transitions))
+}
+
+// isPeerUp tells whether a peer counts as healthy: up, or degraded but not down yet. The results of the
+// instances not tracking the state of their peers fall back on the outcome of the last ping
+func isPeerUp(result models.PodResult) bool {
+	if result.State == "" {
+		return result.OK != nil && *result.OK
+	}
+	return result.State == models.PodResultStateUp || result.State == models.PodResultStateDegraded
+}
@@ -0,0 +1,123 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+)
+
+func TestPeerStateUpdate(t *testing.T) {
+	previousConfig := GoldpingerConfig
+	defer func() { GoldpingerConfig = previousConfig }()
+	GoldpingerConfig.PeerDownAfter = 3
+	GoldpingerConfig.PeerUpAfter = 2
+	GoldpingerConfig.PeerFlapWindow = time.Minute
+	GoldpingerConfig.PeerFlapThreshold = 4
+
+	const (
+		up        = models.PodResultStateUp
+		degraded  = models.PodResultStateDegraded
+		down      = models.PodResultStateDown
+		flapping  = models.PodResultStateFlapping
+		succeeded = true
+		failed    = false
+	)
+	tests := []struct {
+		name            string
+		pings           []bool
+		wantStates      []string
+		wantTransitions int64
+	}{
+		{"up", []bool{succeeded, succeeded}, []string{up, up}, 0},
+		{"dropped pings", []bool{succeeded, failed, failed, succeeded}, []string{up, degraded, degraded, up}, 0},
+		{"down after enough failures", []bool{succeeded, failed, failed, failed}, []string{up, degraded, degraded, down}, 1},
+		{"down from the start", []bool{failed, failed, failed}, []string{degraded, degraded, down}, 0},
+		{"up after enough successes", []bool{failed, failed, failed, succeeded, succeeded}, []string{degraded, degraded, down, down, up}, 1},
+		{"a success while down isn't enough", []bool{failed, failed, failed, succeeded, failed, succeeded}, []string{degraded, degraded, down, down, down, down}, 0},
+		{
+			"flapping",
+			[]bool{succeeded, failed, failed, failed, succeeded, succeeded, failed, failed, failed, succeeded, succeeded},
+			[]string{up, degraded, degraded, down, down, up, degraded, degraded, down, down, flapping},
+			4,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var state peerState
+			now := time.Now()
+			var result models.PodResult
+			for i, ok := range test.pings {
+				result = models.PodResult{}
+				state.update(&result, ok, now.Add(time.Duration(i)*time.Second))
+				if result.State != test.wantStates[i] {
+					t.Errorf("state after ping %d = %q, want %q", i+1, result.State, test.wantStates[i])
+				}
+			}
+			if result.Transitions != test.wantTransitions {
+				t.Errorf("%d transitions, want %d", result.Transitions, test.wantTransitions)
+			}
+		})
+	}
+}
+
+func TestPeerStateForgetsTheTransitionsOutOfTheFlapWindow(t *testing.T) {
+	previousConfig := GoldpingerConfig
+	defer func() { GoldpingerConfig = previousConfig }()
+	GoldpingerConfig.PeerDownAfter = 1
+	GoldpingerConfig.PeerUpAfter = 1
+	GoldpingerConfig.PeerFlapWindow = time.Minute
+	GoldpingerConfig.PeerFlapThreshold = 3
+
+	var state peerState
+	var result models.PodResult
+	now := time.Now()
+	for i, ok := range []bool{true, false, true, false} {
+		state.update(&result, ok, now.Add(time.Duration(i)*time.Second))
+	}
+	if result.State != models.PodResultStateFlapping {
+		t.Fatalf("state = %q, want %q", result.State, models.PodResultStateFlapping)
+	}
+	state.update(&result, false, now.Add(2*time.Minute))
+	if result.State != models.PodResultStateDown || result.Transitions != 0 {
+		t.Errorf("state = %q with %d transitions, want %q with none", result.State, result.Transitions, models.PodResultStateDown)
+	}
+}
+
+func TestIsPeerUp(t *testing.T) {
+	succeeded, failed := true, false
+	tests := []struct {
+		name   string
+		result models.PodResult
+		want   bool
+	}{
+		{"up", models.PodResult{State: models.PodResultStateUp, OK: &failed}, true},
+		{"degraded", models.PodResult{State: models.PodResultStateDegraded, OK: &failed}, true},
+		{"down", models.PodResult{State: models.PodResultStateDown, OK: &succeeded}, false},
+		{"flapping", models.PodResult{State: models.PodResultStateFlapping, OK: &succeeded}, false},
+		{"no state, succeeded", models.PodResult{OK: &succeeded}, true},
+		{"no state, failed", models.PodResult{OK: &failed}, false},
+		{"no state, no outcome", models.PodResult{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isPeerUp(test.result); got != test.want {
+				t.Errorf("isPeerUp() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
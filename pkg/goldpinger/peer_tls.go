@@ -0,0 +1,283 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// PeerIdentityPod checks that the certificate of a peer is issued to its pod name
+	PeerIdentityPod = "pod"
+	// PeerIdentityNode checks that the certificate of a peer is issued to its node name
+	PeerIdentityNode = "node"
+	// PeerIdentityNone only checks that the certificate of a peer is issued by the peer CA
+	PeerIdentityNone = "none"
+)
+
+// PeerTLSError is a failure to establish mutual TLS with a peer
+type PeerTLSError struct {
+	Err error
+}
+
+func (e *PeerTLSError) Error() string {
+	return "peer TLS: " + e.Err.Error()
+}
+
+func (e *PeerTLSError) Unwrap() error {
+	return e.Err
+}
+
+// isPeerTLSError tells whether an error comes from the TLS handshake with a peer
+func isPeerTLSError(err error) bool {
+	var peerErr *PeerTLSError
+	var recordErr tls.RecordHeaderError
+	var verificationErr *tls.CertificateVerificationError
+	if errors.As(err, &peerErr) || errors.As(err, &recordErr) || errors.As(err, &verificationErr) {
+		return true
+	}
+	// the TLS alerts sent by the peer, such as a rejected client certificate
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "remote error"
+}
+
+// peerTLSState holds the peer certificate and CA pool, reloaded when their files change
+type peerTLSState struct {
+	mux      sync.RWMutex
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+	modTimes [3]time.Time
+}
+
+var peerTLS peerTLSState
+
+// PeerTLSEnabled tells whether the peers call each other over mutual TLS
+func PeerTLSEnabled() bool {
+	return GoldpingerConfig.PeerTLSCert != ""
+}
+
+// LoadPeerTLS checks the peer TLS settings, loads the certificate and CA, and starts watching them for changes
+func LoadPeerTLS() error {
+	config := &GoldpingerConfig
+	if config.PeerTLSCert == "" && config.PeerTLSKey == "" && config.PeerTLSCA == "" {
+		return nil
+	}
+	if config.PeerTLSCert == "" || config.PeerTLSKey == "" || config.PeerTLSCA == "" {
+		return fmt.Errorf("--peer-tls-cert, --peer-tls-key and --peer-tls-ca are all required for peer TLS")
+	}
+	switch config.PeerTLSIdentity {
+	case PeerIdentityPod, PeerIdentityNode, PeerIdentityNone:
+	default:
+		return fmt.Errorf("unknown peer identity %q, must be one of %s, %s or %s",
+			config.PeerTLSIdentity, PeerIdentityPod, PeerIdentityNode, PeerIdentityNone)
+	}
+	if _, err := peerTLS.reload(); err != nil {
+		return err
+	}
+	if config.PeerTLSReload > 0 {
+		go watchPeerTLS(config.PeerTLSReload)
+	}
+	return nil
+}
+
+// watchPeerTLS reloads the peer certificate and CA when their files change
+func watchPeerTLS(interval time.Duration) {
+	for range time.Tick(interval) {
+		reloaded, err := peerTLS.reload()
+		if err != nil {
+			zap.L().Error("Error reloading the peer certificate, keeping the previous one", zap.Error(err))
+			CountError("peer_tls")
+		} else if reloaded {
+			zap.L().Info("Reloaded the peer certificate")
+		}
+	}
+}
+
+// reload loads the peer certificate and CA if any of their files changed since the last load
+func (s *peerTLSState) reload() (bool, error) {
+	files := [3]string{GoldpingerConfig.PeerTLSCert, GoldpingerConfig.PeerTLSKey, GoldpingerConfig.PeerTLSCA}
+	var modTimes [3]time.Time
+	for i, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return false, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	s.mux.RLock()
+	unchanged := s.cert != nil && modTimes == s.modTimes
+	s.mux.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(files[0], files[1])
+	if err != nil {
+		return false, fmt.Errorf("could not load the peer certificate: %w", err)
+	}
+	ca, err := os.ReadFile(files[2])
+	if err != nil {
+		return false, fmt.Errorf("could not read the peer CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return false, fmt.Errorf("could not parse the peer CA")
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.cert = &cert
+	s.pool = pool
+	s.modTimes = modTimes
+	return true, nil
+}
+
+func (s *peerTLSState) getCertificate() *tls.Certificate {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.cert
+}
+
+func (s *peerTLSState) getPool() *x509.CertPool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.pool
+}
+
+// getPeerIdentity returns the name the certificate of a peer is expected to be issued to
+func getPeerIdentity(pod *GoldpingerPod) string {
+	switch GoldpingerConfig.PeerTLSIdentity {
+	case PeerIdentityPod:
+		if pod.PodName != "" {
+			return pod.PodName
+		}
+	case PeerIdentityNode:
+		if pod.NodeName != "" {
+			return pod.NodeName
+		}
+	default:
+		return ""
+	}
+	return pod.Name
+}
+
+// verifyPeerCertificate checks that the certificate of a peer is issued by the peer CA and,
+// unless the identity is empty, to the given identity
+func verifyPeerCertificate(certs []*x509.Certificate, identity string) error {
+	if len(certs) == 0 {
+		return &PeerTLSError{Err: errors.New("the peer presented no certificate")}
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         peerTLS.getPool(),
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		return &PeerTLSError{Err: err}
+	}
+	if identity != "" && certs[0].Subject.CommonName != identity && certs[0].VerifyHostname(identity) != nil {
+		return &PeerTLSError{Err: fmt.Errorf("the peer certificate is not issued to %s", identity)}
+	}
+	return nil
+}
+
+// newPeerTLSConfig returns the TLS config calling a peer over mutual TLS, checking its identity
+func newPeerTLSConfig(identity string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return peerTLS.getCertificate(), nil
+		},
+		// the peers are called by IP, so their certificate is verified against the peer CA and
+		// the expected identity by VerifyConnection instead
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			return verifyPeerCertificate(state.PeerCertificates, identity)
+		},
+	}
+}
+
+// newPeerHTTPClient returns an HTTP client calling a peer over mutual TLS, checking its identity
+func newPeerHTTPClient(identity string) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = newPeerTLSConfig(identity)
+	return &http.Client{Transport: transport}
+}
+
+// peerHTTPClients caches the HTTP clients calling the peers, by address and identity
+var peerHTTPClients = struct {
+	sync.Mutex
+	clients map[string]*http.Client
+}{clients: make(map[string]*http.Client)}
+
+// getPeerHTTPClient returns the cached HTTP client calling a peer over mutual TLS
+func getPeerHTTPClient(host, identity string) *http.Client {
+	peerHTTPClients.Lock()
+	defer peerHTTPClients.Unlock()
+	key := host + "/" + identity
+	client, ok := peerHTTPClients.clients[key]
+	if !ok {
+		client = newPeerHTTPClient(identity)
+		peerHTTPClients.clients[key] = client
+	}
+	return client
+}
+
+// evictPeerHTTPClient drops the cached HTTP client calling a peer, if any, and closes its idle connections
+func evictPeerHTTPClient(host, identity string) {
+	peerHTTPClients.Lock()
+	key := host + "/" + identity
+	client, ok := peerHTTPClients.clients[key]
+	delete(peerHTTPClients.clients, key)
+	peerHTTPClients.Unlock()
+	if ok {
+		client.CloseIdleConnections()
+	}
+}
+
+// ConfigurePeerTLSServer makes the TLS listener of the API serve the peer certificate, and verify
+// the client certificates against the peer CA, picking up the reloaded files on each handshake
+func ConfigurePeerTLSServer(tlsConfig *tls.Config) {
+	if !PeerTLSEnabled() {
+		return
+	}
+	tlsConfig.Certificates = nil
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	tlsConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		config := tlsConfig.Clone()
+		config.GetConfigForClient = nil
+		config.Certificates = []tls.Certificate{*peerTLS.getCertificate()}
+		config.ClientCAs = peerTLS.getPool()
+		return config, nil
+	}
+	// the listener needs a certificate before GetConfigForClient is used
+	tlsConfig.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return peerTLS.getCertificate(), nil
+	}
+}
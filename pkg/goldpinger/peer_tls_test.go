@@ -0,0 +1,54 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import "testing"
+
+func TestStoppedPingerEvictsItsPeerHTTPClients(t *testing.T) {
+	previousConfig := GoldpingerConfig
+	defer func() { GoldpingerConfig = previousConfig }()
+	GoldpingerConfig.PeerTLSCert = "cert.pem"
+	GoldpingerConfig.PeerTLSIdentity = PeerIdentityPod
+	GoldpingerConfig.NodePort = 30080
+
+	pod := &GoldpingerPod{Name: "p1", PodName: "p1", PodIP: "10.0.0.1", HostIP: "192.168.0.1", Port: 8080, Role: RolePeer}
+	other := &GoldpingerPod{Name: "p2", PodName: "p2", PodIP: "10.0.0.2", HostIP: "192.168.0.2", Port: 8080, Role: RolePeer}
+	paths := []string{PingPathPod, PingPathNodePort}
+	for _, p := range []*GoldpingerPod{pod, other} {
+		for _, path := range paths {
+			ip, port := getPathAddress(p, path)
+			if _, err := getClient(ip, port, getPathIdentity(p, path)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	countClients := func() int {
+		peerHTTPClients.Lock()
+		defer peerHTTPClients.Unlock()
+		return len(peerHTTPClients.clients)
+	}
+	before := countClients()
+
+	pinger := &Pinger{pod: pod, paths: paths}
+	pinger.closeClients()
+	if after := countClients(); after != before-2 {
+		t.Errorf("%d cached clients once the pinger stopped, want %d", after, before-2)
+	}
+	peerHTTPClients.Lock()
+	defer peerHTTPClients.Unlock()
+	if _, ok := peerHTTPClients.clients["10.0.0.2:8080/p2"]; !ok {
+		t.Error("the client of another peer was evicted")
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+	"github.com/go-openapi/runtime"
+)
+
+// errConnectTimeout marks the timeouts hit before a connection to the peer was established
+var errConnectTimeout = errors.New("timed out connecting")
+
+// isTimeout tells whether an error is a timeout
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// callWithConnectTrace makes a call to a peer, telling the timeouts hit while connecting apart from the
+// ones hit while waiting for the response: the HTTP client reports both as the context deadline
+func callWithConnectTrace(ctx context.Context, call func(ctx context.Context) (*models.PingResults, error)) (*models.PingResults, error) {
+	var connected atomic.Bool
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) {
+			connected.Store(true)
+		},
+	})
+	payload, err := call(ctx)
+	if err != nil && !connected.Load() && isTimeout(err) {
+		err = fmt.Errorf("%w: %w", errConnectTimeout, err)
+	}
+	return payload, err
+}
+
+// classifyPeerError returns why a call to a peer failed
+func classifyPeerError(err error) string {
+	var dnsErr *net.DNSError
+	var apiErr *runtime.APIError
+	var opErr *net.OpError
+	switch {
+	case isPeerTLSError(err):
+		return models.PodResultErrorReasonTLS
+	case errors.As(err, &dnsErr):
+		return models.PodResultErrorReasonDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return models.PodResultErrorReasonConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		return models.PodResultErrorReasonReset
+	case errors.As(err, &apiErr):
+		return models.PodResultErrorReasonHTTPStatus
+	case errors.Is(err, errConnectTimeout), errors.As(err, &opErr) && opErr.Op == "dial" && opErr.Timeout():
+		return models.PodResultErrorReasonConnectTimeout
+	case isTimeout(err):
+		return models.PodResultErrorReasonReadTimeout
+	default:
+		return models.PodResultErrorReasonOther
+	}
+}
+
+// countPeerCallError counts a failed call to a peer with the reason it failed, as a peer_tls error
+// when the TLS handshake failed
+func countPeerCallError(callType string, err error) {
+	reason := classifyPeerError(err)
+	if reason == models.PodResultErrorReasonTLS {
+		callType = "peer_tls"
+	}
+	CountErrorWithReason(callType, reason)
+}
+
+// getErrorStatusCode returns the status code reported for a failed call to a peer: the status code
+// the peer answered with, 504 for the timeouts, and 502 for the other errors
+func getErrorStatusCode(err error, reason string) int32 {
+	var apiErr *runtime.APIError
+	switch reason {
+	case models.PodResultErrorReasonHTTPStatus:
+		if errors.As(err, &apiErr) {
+			return int32(apiErr.Code)
+		}
+		return http.StatusBadGateway
+	case models.PodResultErrorReasonConnectTimeout, models.PodResultErrorReasonReadTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// isRetryable tells whether a failed ping may succeed if retried right away. The TLS errors and
+// the error statuses answered by the peer won't go away on their own
+func isRetryable(reason string) bool {
+	return reason != models.PodResultErrorReasonTLS && reason != models.PodResultErrorReasonHTTPStatus
+}
+
+// getRetryDelay returns how long to wait before the given retry of a ping, doubling with each retry
+func getRetryDelay(retry int) time.Duration {
+	return GoldpingerConfig.PingRetryDelay << (retry - 1)
+}
+
+// pingDeadlineShare is the share of the refresh period a ping may take, retries included, so that
+// it's done before the next one
+const pingDeadlineShare = 0.8
+
+// getPingDeadline returns when a ping started at the given time has to be done, retries included,
+// or the zero time when the peers aren't pinged periodically
+func getPingDeadline(start time.Time) time.Time {
+	if GoldpingerConfig.RefreshInterval <= 0 {
+		return time.Time{}
+	}
+	period := time.Duration(GoldpingerConfig.RefreshInterval) * time.Second
+	return start.Add(time.Duration(float64(period) * pingDeadlineShare))
+}
+
+// canRetry tells whether a retry made after the given delay, and taking up to the ping timeout,
+// would be done before the deadline of the ping
+func canRetry(now, deadline time.Time, delay, timeout time.Duration) bool {
+	return deadline.IsZero() || !now.Add(delay+timeout).After(deadline)
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+	"github.com/go-openapi/runtime"
+)
+
+func TestClassifyPeerError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantReason string
+		wantStatus int32
+		wantRetry  bool
+	}{
+		{"tls", &PeerTLSError{Err: errors.New("bad certificate")}, models.PodResultErrorReasonTLS, http.StatusBadGateway, false},
+		{"tls record", tls.RecordHeaderError{Msg: "not a TLS handshake"}, models.PodResultErrorReasonTLS, http.StatusBadGateway, false},
+		{"dns", &net.DNSError{Err: "no such host", Name: "peer"}, models.PodResultErrorReasonDNS, http.StatusBadGateway, true},
+		{"refused", &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, models.PodResultErrorReasonConnectionRefused, http.StatusBadGateway, true},
+		{"reset", &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, models.PodResultErrorReasonReset, http.StatusBadGateway, true},
+		{"eof", fmt.Errorf("reading the response: %w", io.EOF), models.PodResultErrorReasonReset, http.StatusBadGateway, true},
+		{"http status", runtime.NewAPIError("unknown error", nil, http.StatusServiceUnavailable), models.PodResultErrorReasonHTTPStatus, http.StatusServiceUnavailable, false},
+		{"connect timeout", fmt.Errorf("%w: %w", errConnectTimeout, context.DeadlineExceeded), models.PodResultErrorReasonConnectTimeout, http.StatusGatewayTimeout, true},
+		{"read timeout", context.DeadlineExceeded, models.PodResultErrorReasonReadTimeout, http.StatusGatewayTimeout, true},
+		{"other", errors.New("something else"), models.PodResultErrorReasonOther, http.StatusBadGateway, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reason := classifyPeerError(test.err)
+			if reason != test.wantReason {
+				t.Errorf("classifyPeerError() = %q, want %q", reason, test.wantReason)
+			}
+			if status := getErrorStatusCode(test.err, reason); status != test.wantStatus {
+				t.Errorf("getErrorStatusCode() = %d, want %d", status, test.wantStatus)
+			}
+			if retry := isRetryable(reason); retry != test.wantRetry {
+				t.Errorf("isRetryable() = %t, want %t", retry, test.wantRetry)
+			}
+		})
+	}
+}
+
+func TestGetRetryDelay(t *testing.T) {
+	previousDelay := GoldpingerConfig.PingRetryDelay
+	GoldpingerConfig.PingRetryDelay = 100 * time.Millisecond
+	defer func() { GoldpingerConfig.PingRetryDelay = previousDelay }()
+
+	for retry, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		5: 1600 * time.Millisecond,
+	} {
+		if got := getRetryDelay(retry); got != want {
+			t.Errorf("getRetryDelay(%d) = %s, want %s", retry, got, want)
+		}
+	}
+}
+
+func TestCanRetry(t *testing.T) {
+	previousRefreshInterval := GoldpingerConfig.RefreshInterval
+	defer func() { GoldpingerConfig.RefreshInterval = previousRefreshInterval }()
+	start := time.Now()
+
+	tests := []struct {
+		name            string
+		refreshInterval int
+		elapsed         time.Duration
+		delay           time.Duration
+		want            bool
+	}{
+		{"first retry", 30, 300 * time.Millisecond, 100 * time.Millisecond, true},
+		{"last retry before the deadline", 1, 300 * time.Millisecond, 200 * time.Millisecond, true},
+		{"retry past the deadline", 1, 300 * time.Millisecond, 400 * time.Millisecond, false},
+		{"retry delay past the refresh period", 30, time.Second, 40 * time.Second, false},
+		{"not pinging periodically", 0, time.Minute, time.Hour, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			GoldpingerConfig.RefreshInterval = test.refreshInterval
+			deadline := getPingDeadline(start)
+			if got := canRetry(start.Add(test.elapsed), deadline, test.delay, 300*time.Millisecond); got != test.want {
+				t.Errorf("canRetry() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
@@ -16,6 +16,9 @@ package goldpinger
 
 import (
 	"context"
+	"errors"
+	"net"
+	"strconv"
 	"time"
 
 	"go.uber.org/zap"
@@ -31,13 +34,18 @@ import (
 // Pinger contains all the info needed by a goroutine to continuously ping a pod
 type Pinger struct {
 	pod         *GoldpingerPod
-	client      *apiclient.Goldpinger
+	paths       []string
+	clients     map[string]*apiclient.Goldpinger
+	grpcClient  *grpcClient
 	timeout     time.Duration
-	histogram   prometheus.Observer
+	histograms  map[string]prometheus.Observer
 	hostIPv4    strfmt.IPv4
 	podIPv4     strfmt.IPv4
 	resultsChan chan<- PingAllPodsResult
 	stopChan    chan struct{}
+	outages     map[string]bool
+	state       peerState
+	started     time.Time
 	logger      *zap.Logger
 }
 
@@ -46,24 +54,37 @@ type Pinger struct {
 func NewPinger(pod *GoldpingerPod, resultsChan chan<- PingAllPodsResult) *Pinger {
 	p := Pinger{
 		pod:         pod,
+		paths:       getPingPaths(),
+		clients:     make(map[string]*apiclient.Goldpinger),
 		timeout:     GoldpingerConfig.PingTimeout,
 		resultsChan: resultsChan,
 		stopChan:    make(chan struct{}),
-
-		histogram: goldpingerResponseTimePeersHistogram.WithLabelValues(
-			GoldpingerConfig.Hostname,
-			"ping",
-			pod.HostIP,
-			pod.PodIP,
-		),
+		outages:     make(map[string]bool),
+		started:     time.Now(),
+		histograms:  make(map[string]prometheus.Observer),
 
 		logger: zap.L().With(
 			zap.String("op", "pinger"),
 			zap.String("name", pod.Name),
 			zap.String("hostIP", pod.HostIP),
 			zap.String("podIP", pod.PodIP),
+			zap.String("source", pod.Source),
 		),
 	}
+	if pod.Role == RoleCanary {
+		// canaries don't run goldpinger, so they are only checked over the primary path
+		p.paths = p.paths[:1]
+	}
+
+	for i, path := range p.paths {
+		p.histograms[path] = goldpingerResponseTimePeersHistogram.WithLabelValues(
+			GoldpingerConfig.Hostname,
+			getPathCallType(path, i == 0),
+			pod.HostIP,
+			pod.PodIP,
+			pod.Source,
+		)
+	}
 
 	// Initialize the host/pod IPv4
 	p.hostIPv4.UnmarshalText([]byte(pod.HostIP))
@@ -72,84 +93,264 @@ func NewPinger(pod *GoldpingerPod, resultsChan chan<- PingAllPodsResult) *Pinger
 	return &p
 }
 
-// getClient returns a client that can be used to ping the given pod
-// On error, it returns a static result
-func (p *Pinger) getClient() (*apiclient.Goldpinger, error) {
-	if p.client != nil {
-		return p.client, nil
+// getCall returns a function that pings the given pod over the given path
+func (p *Pinger) getCall(path string) (func(ctx context.Context) (*models.PingResults, error), error) {
+	ip, port := getPathAddress(p.pod, path)
+	if p.pod.Role == RoleCanary {
+		if ip == "" {
+			return nil, errors.New("Host or pod IP empty, can't make a call")
+		}
+		addr := net.JoinHostPort(ip, strconv.Itoa(port))
+		return func(ctx context.Context) (*models.PingResults, error) {
+			// canaries only get a TCP check, which doesn't return any stats
+			var dialer net.Dialer
+			conn, err := dialer.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				return nil, err
+			}
+			return nil, conn.Close()
+		}, nil
+	}
+	if path == PingPathGRPC {
+		if ip == "" {
+			return nil, errors.New("Pod IP empty, can't make a call")
+		}
+		if p.grpcClient == nil {
+			client, err := newGRPCClient(net.JoinHostPort(ip, strconv.Itoa(port)), getPeerGRPCCredentials(getPeerIdentity(p.pod)))
+			if err != nil {
+				p.logger.Warn("Could not get gRPC client", zap.Error(err))
+				return nil, err
+			}
+			p.grpcClient = client
+		}
+		return func(ctx context.Context) (*models.PingResults, error) {
+			// the gRPC health check doesn't return any stats
+			return nil, p.grpcClient.check(ctx, grpcService)
+		}, nil
 	}
 
-	client, err := getClient(pickPodHostIP(p.pod.PodIP, p.pod.HostIP))
-	if err != nil {
-		p.logger.Warn("Could not get client", zap.Error(err))
-		OK := false
-		p.resultsChan <- PingAllPodsResult{
-			podName: p.pod.Name,
-			podResult: models.PodResult{
-				PingTime:       strfmt.DateTime(time.Now()),
-				PodIP:          p.podIPv4,
-				HostIP:         p.hostIPv4,
-				OK:             &OK,
-				Error:          err.Error(),
-				StatusCode:     500,
-				ResponseTimeMs: 0,
-			},
+	client, ok := p.clients[path]
+	if !ok {
+		var err error
+		client, err = getClient(ip, port, getPathIdentity(p.pod, path))
+		if err != nil {
+			p.logger.Warn("Could not get client", zap.String("path", path), zap.Error(err))
+			return nil, err
 		}
-		return nil, err
+		p.clients[path] = client
 	}
-	p.client = client
-	return p.client, nil
+	return func(ctx context.Context) (*models.PingResults, error) {
+		params := operations.NewPingParamsWithContext(ctx)
+		resp, err := client.Operations.Ping(params)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Payload, nil
+	}, nil
 }
 
-// Ping makes a single ping request to the given pod
-func (p *Pinger) Ping() {
-	client, err := p.getClient()
-	if err != nil {
+// getPathIdentity returns the name the certificate of the given pod is expected to be issued to, when
+// pinged over the given path
+func getPathIdentity(pod *GoldpingerPod, path string) string {
+	if path == PingPathNodePort {
+		// the NodePort forwards the ping to any instance, so only the CA can be checked
+		return ""
+	}
+	return getPeerIdentity(pod)
+}
+
+// closeClients closes the connections to the pod, and drops the peer TLS clients cached for it,
+// once the pinger is stopped
+func (p *Pinger) closeClients() {
+	if p.grpcClient != nil {
+		p.grpcClient.close()
+	}
+	if !PeerTLSEnabled() {
 		return
 	}
+	for _, path := range p.paths {
+		if path == PingPathGRPC {
+			continue
+		}
+		ip, port := getPathAddress(p.pod, path)
+		evictPeerHTTPClient(net.JoinHostPort(ip, strconv.Itoa(port)), getPathIdentity(p.pod, path))
+	}
+	// the client calling /check on the pod for /check_all
+	evictPeerHTTPClient(net.JoinHostPort(pickPodHostIP(p.pod.PodIP, p.pod.HostIP), strconv.Itoa(getPodPort(p.pod))), getPeerIdentity(p.pod))
+}
+
+// estimateClockOffset estimates, NTP-style, how far the peer's clock is from ours.
+// It assumes the request and the response took as long to travel
+func estimateClockOffset(start time.Time, responseTime time.Duration, serverTime strfmt.DateTime) time.Duration {
+	return time.Time(serverTime).Sub(start.Add(responseTime / 2))
+}
+
+// pingPath makes a single ping request to the given pod over the given path.
+// It also returns the estimated clock offset of the pod, if the pod reported its time
+func (p *Pinger) pingPath(path string, primary bool) (models.PathResult, *models.PingResults, *time.Duration) {
+	OK := false
+	call, err := p.getCall(path)
+	if err != nil {
+		return models.PathResult{
+			OK:             &OK,
+			Error:          err.Error(),
+			StatusCode:     500,
+			ResponseTimeMs: 0,
+		}, nil, nil
+	}
+
+	callType := getPathCallType(path, primary)
+	maxAttempts := 1
+	if !p.outages[path] {
+		maxAttempts += GoldpingerConfig.PingRetries
+	}
+	var start time.Time
+	var responseTime time.Duration
+	var payload *models.PingResults
+	attempts := 0
+	deadline := getPingDeadline(time.Now())
+retry:
+	for {
+		attempts++
+		start = time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+		payload, err = callWithConnectTrace(ctx, call)
+		cancel()
+		responseTime = time.Since(start)
+		p.histograms[path].Observe(responseTime.Seconds())
+		if err == nil || attempts >= maxAttempts {
+			break
+		}
+		reason := classifyPeerError(err)
+		delay := getRetryDelay(attempts)
+		if !isRetryable(reason) || !canRetry(time.Now(), deadline, delay, p.timeout) {
+			break
+		}
+		// count the retried attempts apart from the failed pings, to tell the dropped packets from the outages
+		p.logger.Debug("Retrying ping", zap.String("path", path), zap.String("reason", reason), zap.Int("attempt", attempts), zap.Error(err))
+		CountCall("retried", callType)
+		select {
+		case <-time.After(delay):
+		case <-p.stopChan:
+			// the pinger is stopping, don't retry
+			break retry
+		}
+	}
+	responseTimeMs := responseTime.Nanoseconds() / int64(time.Millisecond)
+	if primary && GoldpingerConfig.ZoneMetrics {
+		ObserveZoneResponseTime(getLocalTopology().Zone, p.pod.Zone, responseTime)
+	}
+
+	OK = (err == nil)
+	if !OK {
+		reason := classifyPeerError(err)
+		p.logger.Warn("Ping returned error", zap.String("path", path), zap.String("reason", reason), zap.Int("attempts", attempts), zap.Duration("responseTime", responseTime), zap.Error(err))
+		countPeerCallError(callType, err)
+		// a peer failing all its retries is in an outage, and isn't retried until it answers again
+		p.outages[path] = GoldpingerConfig.PingRetries > 0
+		return models.PathResult{
+			OK:             &OK,
+			Attempts:       int64(attempts),
+			Error:          err.Error(),
+			ErrorReason:    reason,
+			StatusCode:     getErrorStatusCode(err, reason),
+			ResponseTimeMs: responseTimeMs,
+		}, nil, nil
+	}
+	if attempts > 1 {
+		p.logger.Info("Ping succeeded after retrying", zap.String("path", path), zap.Int("attempts", attempts))
+		CountCall("recovered", callType)
+	}
+	delete(p.outages, path)
+	p.logger.Debug("Success pinging pod", zap.String("path", path), zap.Duration("responseTime", responseTime))
 
+	var clockOffset *time.Duration
+	if payload != nil && !time.Time(payload.ServerTime).IsZero() {
+		offset := estimateClockOffset(start, responseTime, payload.ServerTime)
+		clockOffset = &offset
+	}
+	return models.PathResult{
+		OK:             &OK,
+		Attempts:       int64(attempts),
+		StatusCode:     200,
+		ResponseTimeMs: responseTimeMs,
+	}, payload, clockOffset
+}
+
+// isUnexpectedSnat checks whether the peer saw the ping come from another IP than ours.
+// Pod to pod traffic should never be masqueraded, so this is only relevant for the pod path
+func (p *Pinger) isUnexpectedSnat(response *models.PingResults) bool {
+	if GoldpingerConfig.PodIP == "" || response.SourceIP == "" {
+		// we don't know what to expect, or the peer doesn't report it
+		return false
+	}
+	detected := response.SourceIP != GoldpingerConfig.PodIP
+	if detected {
+		p.logger.Warn(
+			"Peer saw ping coming from an unexpected source IP",
+			zap.String("sourceIP", response.SourceIP),
+			zap.String("expectedSourceIP", GoldpingerConfig.PodIP),
+		)
+	}
+	SetUnexpectedSnat(p.pod.HostIP, p.pod.PodIP, detected)
+	return detected
+}
+
+// Ping makes a single ping request to the given pod over each of the configured paths
+func (p *Pinger) Ping() {
 	CountCall("made", "ping")
 	start := time.Now()
 
-	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
-	defer cancel()
+	OK := true
+	podResult := models.PodResult{
+		PingTime:    strfmt.DateTime(start),
+		PodIP:       p.podIPv4,
+		HostIP:      p.hostIPv4,
+		OK:          &OK,
+		Zone:        p.pod.Zone,
+		Region:      p.pod.Region,
+		NodeLabels:  p.pod.NodeLabels,
+		Source:      p.pod.Source,
+		Role:        p.pod.Role,
+		Maintenance: p.pod.Maintenance,
+		Departed:    isDeparted(p.pod.PodIP),
+	}
+	if len(p.paths) > 1 {
+		podResult.PathResults = make(map[string]models.PathResult)
+	}
 
-	params := operations.NewPingParamsWithContext(ctx)
-	resp, err := client.Operations.Ping(params)
-	responseTime := time.Since(start)
-	responseTimeMs := responseTime.Nanoseconds() / int64(time.Millisecond)
-	p.histogram.Observe(responseTime.Seconds())
-
-	OK := (err == nil)
-	if OK {
-		p.resultsChan <- PingAllPodsResult{
-			podName: p.pod.Name,
-			podResult: models.PodResult{
-				PingTime:       strfmt.DateTime(start),
-				PodIP:          p.podIPv4,
-				HostIP:         p.hostIPv4,
-				OK:             &OK,
-				Response:       resp.Payload,
-				StatusCode:     200,
-				ResponseTimeMs: responseTimeMs,
-			},
+	for i, path := range p.paths {
+		pathResult, response, clockOffset := p.pingPath(path, i == 0)
+		if i == 0 {
+			// the primary path fills in the top-level fields
+			podResult.Response = response
+			podResult.StatusCode = pathResult.StatusCode
+			podResult.ResponseTimeMs = pathResult.ResponseTimeMs
+			podResult.Attempts = pathResult.Attempts
+			podResult.Error = pathResult.Error
+			podResult.ErrorReason = pathResult.ErrorReason
+			if clockOffset != nil {
+				podResult.ClockOffsetMs = clockOffset.Milliseconds()
+				SetPeerClockOffset(p.pod.HostIP, p.pod.PodIP, *clockOffset)
+			}
+		} else if podResult.Error == "" && pathResult.Error != "" {
+			podResult.Error = path + " path: " + pathResult.Error
+			podResult.ErrorReason = pathResult.ErrorReason
 		}
-		p.logger.Debug("Success pinging pod", zap.Duration("responseTime", responseTime))
-	} else {
-		p.resultsChan <- PingAllPodsResult{
-			podName: p.pod.Name,
-			podResult: models.PodResult{
-				PingTime:       strfmt.DateTime(start),
-				PodIP:          p.podIPv4,
-				HostIP:         p.hostIPv4,
-				OK:             &OK,
-				Error:          err.Error(),
-				StatusCode:     504,
-				ResponseTimeMs: responseTimeMs,
-			},
+		if podResult.PathResults != nil {
+			podResult.PathResults[path] = pathResult
 		}
-		p.logger.Warn("Ping returned error", zap.Duration("responseTime", responseTime), zap.Error(err))
-		CountError("ping")
+		if path == PingPathPod && response != nil {
+			podResult.UnexpectedSnat = p.isUnexpectedSnat(response)
+		}
+		// a failure on any of the paths makes the peer unhealthy
+		OK = OK && *pathResult.OK
+	}
+	p.state.update(&podResult, OK, time.Now())
+
+	p.resultsChan <- PingAllPodsResult{
+		podName:   p.pod.Name,
+		podResult: podResult,
 	}
 }
 
@@ -171,6 +372,7 @@ func (p *Pinger) PingContinuously(initialWait time.Duration, period time.Duratio
 	case <-p.stopChan:
 		// Do nothing
 	}
+	p.closeClients()
 	// We are done, send a message on the results channel to delete this
 	p.resultsChan <- PingAllPodsResult{podName: p.pod.Name, deleted: true}
 }
@@ -15,26 +15,266 @@
 package goldpinger
 
 import (
+	"fmt"
+	"sort"
+	"sync"
+
 	"github.com/cespare/xxhash"
 	rendezvous "github.com/stuartnelson3/go-rendezvous"
 )
 
+const (
+	// PingStrategyRendezvous selects the peers to ping with a rendezvous hash
+	PingStrategyRendezvous = "rendezvous"
+	// PingStrategyTopology selects at least --min-peers-per-zone peers in each zone, then
+	// fills the rest with a rendezvous hash
+	PingStrategyTopology = "topology"
+	// PingStrategyRing places the pods on a consistent hash ring, and has each pod pinged by the
+	// --min-pingers peers that precede it on the ring, then fills the rest with a rendezvous hash.
+	// Every pod is then pinged by at least --min-pingers others
+	PingStrategyRing = "ring"
+)
+
+// ValidatePeerSelection checks that the configured ping strategy can be used
+func ValidatePeerSelection() error {
+	switch GoldpingerConfig.PingStrategy {
+	case PingStrategyRendezvous, PingStrategyRing:
+	case PingStrategyTopology:
+		if !GoldpingerConfig.DiscoverTopology {
+			return fmt.Errorf("the %s ping strategy requires --discover-topology to be set", GoldpingerConfig.PingStrategy)
+		}
+		if GoldpingerConfig.TopologyKey == "" {
+			return fmt.Errorf("the %s ping strategy requires --topology-key to be set", GoldpingerConfig.PingStrategy)
+		}
+	default:
+		return fmt.Errorf("unknown ping strategy: %q", GoldpingerConfig.PingStrategy)
+	}
+	return nil
+}
+
 // SelectPods selects a set of pods from the results of GetAllPods
-// depending on the count according to a rendezvous hash
+// depending on the count according to the configured ping strategy
 func SelectPods() map[string]*GoldpingerPod {
-	allPods := GetAllPods()
+	return selectPodsFor(getLocalPodKey(), GetAllPods())
+}
+
+// getLocalPodKey returns the key of this goldpinger pod in the results of GetAllPods
+func getLocalPodKey() string {
+	if len(discoverySources) > 1 {
+		return PodNamespace + "/" + GoldpingerConfig.PodName
+	}
+	return GoldpingerConfig.PodName
+}
+
+// selectPodsFor selects the pods that the given goldpinger pod pings. It only depends on its
+// arguments, so that the selection of any instance can be computed from anywhere
+func selectPodsFor(podName string, allPods map[string]*GoldpingerPod) map[string]*GoldpingerPod {
 	if GoldpingerConfig.PingNumber <= 0 || int(GoldpingerConfig.PingNumber) >= len(allPods) {
 		return allPods
 	}
 
+	switch GoldpingerConfig.PingStrategy {
+	case PingStrategyTopology:
+		return selectPodsByTopology(podName, allPods)
+	case PingStrategyRing:
+		return selectPodsByRing(podName, allPods)
+	default:
+		return selectPodsByRendezvous(podName, allPods, GoldpingerConfig.PingNumber)
+	}
+}
+
+// rankPodsByRendezvous returns the names of the first count pods in the rendezvous hash order of the given pod
+func rankPodsByRendezvous(podName string, pods map[string]*GoldpingerPod, count uint) []string {
 	rzv := rendezvous.New([]string{}, rendezvous.Hasher(xxhash.Sum64String))
-	for podName := range allPods {
-		rzv.Add(podName)
+	for name := range pods {
+		rzv.Add(name)
+	}
+	return rzv.LookupN(podName, count)
+}
+
+// selectPodsByRendezvous selects count pods according to a rendezvous hash
+func selectPodsByRendezvous(podName string, pods map[string]*GoldpingerPod, count uint) map[string]*GoldpingerPod {
+	toPing := make(map[string]*GoldpingerPod)
+	for _, name := range rankPodsByRendezvous(podName, pods, count) {
+		toPing[name] = pods[name]
+	}
+	return toPing
+}
+
+// selectPodsByTopology selects --min-peers-per-zone pods in each zone (or each value of --topology-key),
+// by rendezvous hash within the zone, then fills up to --ping-number pods by rendezvous hash.
+// The guarantee wins over --ping-number when there are more zones than it allows for
+func selectPodsByTopology(podName string, allPods map[string]*GoldpingerPod) map[string]*GoldpingerPod {
+	domains := make(map[string]map[string]*GoldpingerPod)
+	for name, pod := range allPods {
+		domain := zoneOrUnknown(getTopologyDomain(pod))
+		if domains[domain] == nil {
+			domains[domain] = make(map[string]*GoldpingerPod)
+		}
+		domains[domain][name] = pod
+	}
+
+	toPing := make(map[string]*GoldpingerPod)
+	for _, pods := range domains {
+		for name, pod := range selectPodsByRendezvous(podName, pods, GoldpingerConfig.MinPeersPerZone) {
+			toPing[name] = pod
+		}
+	}
+
+	// walk the whole rendezvous order, skipping the pods already selected
+	for _, name := range rankPodsByRendezvous(podName, allPods, uint(len(allPods))) {
+		if len(toPing) >= int(GoldpingerConfig.PingNumber) {
+			break
+		}
+		toPing[name] = allPods[name]
+	}
+	return toPing
+}
+
+// selectPodsByRing selects the pods that have the given pod among the --min-pingers (at least one)
+// peers preceding them on a consistent hash ring, then fills up to --ping-number pods by rendezvous
+// hash. Only the peers ping, the canaries and gateways are only pinged, so every pod is selected by
+// the --min-pingers peers preceding it. The guarantee wins over --ping-number when --min-pingers is larger
+func selectPodsByRing(podName string, allPods map[string]*GoldpingerPod) map[string]*GoldpingerPod {
+	ring := make([]string, 0, len(allPods))
+	for name := range allPods {
+		ring = append(ring, name)
 	}
-	matches := rzv.LookupN(GoldpingerConfig.PodName, GoldpingerConfig.PingNumber)
+	sort.Slice(ring, func(i, j int) bool {
+		hi, hj := xxhash.Sum64String(ring[i]), xxhash.Sum64String(ring[j])
+		if hi != hj {
+			return hi < hj
+		}
+		return ring[i] < ring[j]
+	})
+
 	toPing := make(map[string]*GoldpingerPod)
-	for _, podName := range matches {
-		toPing[podName] = allPods[podName]
+	successors := int(GoldpingerConfig.MinPingers)
+	if successors < 1 {
+		successors = 1
+	}
+	position := -1
+	for i, name := range ring {
+		if name == podName {
+			position = i
+			break
+		}
+	}
+	if position >= 0 {
+		// walk the ring until passing --min-pingers other peers: the pods up to there have fewer
+		// than --min-pingers peers between the given pod and them
+		passed := 0
+		for i := 1; i < len(ring) && passed < successors; i++ {
+			name := ring[(position+i)%len(ring)]
+			toPing[name] = allPods[name]
+			if isPinger(allPods[name]) {
+				passed++
+			}
+		}
+	}
+
+	// overlay: walk the rendezvous order, skipping ourselves and the pods already selected
+	for _, name := range rankPodsByRendezvous(podName, allPods, uint(len(allPods))) {
+		if len(toPing) >= int(GoldpingerConfig.PingNumber) {
+			break
+		}
+		if name != podName {
+			toPing[name] = allPods[name]
+		}
 	}
 	return toPing
 }
+
+// getTopologyDomain returns the value of --topology-key for the node of the given pod
+func getTopologyDomain(pod *GoldpingerPod) string {
+	switch GoldpingerConfig.TopologyKey {
+	case zoneLabel:
+		return pod.Zone
+	case regionLabel:
+		return pod.Region
+	default:
+		return pod.NodeLabels[GoldpingerConfig.TopologyKey]
+	}
+}
+
+// isPinger tells whether a pod pings others: only the peers do, the canaries and the gateways
+// are only pinged
+func isPinger(pod *GoldpingerPod) bool {
+	return pod.Role == RolePeer
+}
+
+// coverageCache holds the coverage computed for the latest list of pods, computing it takes the
+// selection of every pinger
+var coverageCache = struct {
+	sync.Mutex
+	key      uint64
+	coverage map[string]int64
+}{}
+
+// getPodsKey returns a hash of everything the selection of the pods depends on
+func getPodsKey(allPods map[string]*GoldpingerPod) uint64 {
+	names := make([]string, 0, len(allPods))
+	for name := range allPods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	digest := xxhash.New()
+	for _, name := range names {
+		pod := allPods[name]
+		fmt.Fprintf(digest, "%s\x00%s\x00%s\x00%s\x00", name, pod.Role, pod.HostIP, getTopologyDomain(pod))
+	}
+	return digest.Sum64()
+}
+
+// getCoverage computes the selection of every pinger, and returns how many other pods ping
+// each node (its in-degree), keyed by host IP. The coverage is computed once for each list of pods
+func getCoverage(allPods map[string]*GoldpingerPod) map[string]int64 {
+	key := getPodsKey(allPods)
+	coverageCache.Lock()
+	defer coverageCache.Unlock()
+	if coverageCache.coverage == nil || coverageCache.key != key {
+		coverageCache.key = key
+		coverageCache.coverage = computeCoverage(allPods)
+	}
+	coverage := make(map[string]int64, len(coverageCache.coverage))
+	for node, inDegree := range coverageCache.coverage {
+		coverage[node] = inDegree
+	}
+	return coverage
+}
+
+// computeCoverage computes the selection of every pinger, and returns the in-degree of each node
+func computeCoverage(allPods map[string]*GoldpingerPod) map[string]int64 {
+	coverage := make(map[string]int64, len(allPods))
+	// report the nodes nobody pings too
+	for _, pod := range allPods {
+		coverage[pod.HostIP] = 0
+	}
+	for podName, pod := range allPods {
+		if !isPinger(pod) {
+			continue
+		}
+		for peerName, peer := range selectPodsFor(podName, allPods) {
+			if peerName != podName {
+				coverage[peer.HostIP]++
+			}
+		}
+	}
+	return coverage
+}
+
+// getMinPingers returns the number of other pods that should ping each node, capped by the
+// number of other pingers there are
+func getMinPingers(allPods map[string]*GoldpingerPod) int64 {
+	pingers := int64(0)
+	for _, pod := range allPods {
+		if isPinger(pod) {
+			pingers++
+		}
+	}
+	minPingers := int64(GoldpingerConfig.MinPingers)
+	if others := pingers - 1; minPingers > others {
+		return others
+	}
+	return minPingers
+}
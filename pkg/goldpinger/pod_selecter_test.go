@@ -0,0 +1,171 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// setSelectionConfig sets the configuration of the selection of the pods for the duration of a test
+func setSelectionConfig(t *testing.T, strategy string, pingNumber, minPingers uint) {
+	previousStrategy := GoldpingerConfig.PingStrategy
+	previousPingNumber := GoldpingerConfig.PingNumber
+	previousMinPingers := GoldpingerConfig.MinPingers
+	GoldpingerConfig.PingStrategy = strategy
+	GoldpingerConfig.PingNumber = pingNumber
+	GoldpingerConfig.MinPingers = minPingers
+	t.Cleanup(func() {
+		GoldpingerConfig.PingStrategy = previousStrategy
+		GoldpingerConfig.PingNumber = previousPingNumber
+		GoldpingerConfig.MinPingers = previousMinPingers
+	})
+}
+
+// newTestPods returns peers, canaries and gateways, each on its own node
+func newTestPods(peers, canaries, gateways int) map[string]*GoldpingerPod {
+	pods := make(map[string]*GoldpingerPod)
+	add := func(prefix, role string, count int) {
+		for i := 0; i < count; i++ {
+			name := fmt.Sprintf("%s-%d", prefix, i)
+			pods[name] = &GoldpingerPod{
+				Name:   name,
+				PodIP:  fmt.Sprintf("10.%d.0.%d", len(role), i),
+				HostIP: fmt.Sprintf("192.168.%d.%d", len(role), i),
+				Role:   role,
+			}
+		}
+	}
+	add("peer", RolePeer, peers)
+	add("canary", RoleCanary, canaries)
+	add("gateway", RoleGateway, gateways)
+	return pods
+}
+
+func TestSelectPodsFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		strategy   string
+		pingNumber uint
+		minPingers uint
+		wantCount  int
+	}{
+		{"all the pods", PingStrategyRendezvous, 0, 0, 27},
+		{"rendezvous", PingStrategyRendezvous, 5, 0, 5},
+		{"ring", PingStrategyRing, 5, 2, 5},
+		{"ring with more pingers than pods to ping", PingStrategyRing, 1, 3, 3},
+	}
+	pods := newTestPods(20, 5, 2)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setSelectionConfig(t, test.strategy, test.pingNumber, test.minPingers)
+			selected := selectPodsFor("peer-0", pods)
+			if len(selected) < test.wantCount {
+				t.Errorf("selected %d pods, want at least %d", len(selected), test.wantCount)
+			}
+			if !reflect.DeepEqual(selected, selectPodsFor("peer-0", pods)) {
+				t.Error("the selection isn't deterministic")
+			}
+		})
+	}
+}
+
+func TestRingCoversEveryPod(t *testing.T) {
+	tests := []struct {
+		name                      string
+		peers, canaries, gateways int
+		pingNumber, minPingers    uint
+	}{
+		{"peers only", 30, 0, 0, 3, 2},
+		{"with canaries and gateways", 20, 8, 4, 3, 2},
+		{"mostly canaries", 3, 20, 0, 2, 2},
+		{"consecutive canaries", 4, 40, 0, 1, 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setSelectionConfig(t, PingStrategyRing, test.pingNumber, test.minPingers)
+			pods := newTestPods(test.peers, test.canaries, test.gateways)
+
+			coverage := computeCoverage(pods)
+			if len(coverage) != len(pods) {
+				t.Fatalf("coverage of %d nodes, want %d", len(coverage), len(pods))
+			}
+			minPingers := getMinPingers(pods)
+			sum := int64(0)
+			for node, inDegree := range coverage {
+				if inDegree < minPingers {
+					t.Errorf("node %s is pinged by %d peers, want at least %d", node, inDegree, minPingers)
+				}
+				sum += inDegree
+			}
+			// only the peers ping
+			wantSum := int64(0)
+			for podName, pod := range pods {
+				if pod.Role == RolePeer {
+					wantSum += int64(len(selectPodsFor(podName, pods)))
+				}
+			}
+			if sum != wantSum {
+				t.Errorf("%d pings in total, want %d from the peers", sum, wantSum)
+			}
+		})
+	}
+}
+
+func TestGetCoverageIsCachedForEachListOfPods(t *testing.T) {
+	setSelectionConfig(t, PingStrategyRing, 3, 2)
+	pods := newTestPods(10, 2, 0)
+
+	coverage := getCoverage(pods)
+	if !reflect.DeepEqual(coverage, computeCoverage(pods)) {
+		t.Fatal("the cached coverage differs from the computed one")
+	}
+	key := coverageCache.key
+	coverage["192.168.4.1"] = 1000
+	if getCoverage(pods)["192.168.4.1"] == 1000 {
+		t.Error("the cached coverage was modified through a returned copy")
+	}
+	if coverageCache.key != key {
+		t.Error("the coverage was computed again for the same list of pods")
+	}
+
+	delete(pods, "peer-0")
+	getCoverage(pods)
+	if coverageCache.key == key {
+		t.Error("the coverage wasn't computed again for a new list of pods")
+	}
+}
+
+func TestGetMinPingers(t *testing.T) {
+	tests := []struct {
+		name       string
+		pods       map[string]*GoldpingerPod
+		minPingers uint
+		want       int64
+	}{
+		{"enough peers", newTestPods(10, 0, 0), 3, 3},
+		{"capped by the other peers", newTestPods(3, 10, 0), 5, 2},
+		{"disabled", newTestPods(10, 0, 0), 0, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setSelectionConfig(t, PingStrategyRing, 0, test.minPingers)
+			if got := getMinPingers(test.pods); got != test.want {
+				t.Errorf("getMinPingers() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"fmt"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ExpectAllow is the default expectation: the probe should succeed
+	ExpectAllow = "allow"
+	// ExpectDeny marks targets that should be unreachable, for example because of a NetworkPolicy
+	ExpectDeny = "deny"
+)
+
+// ProbeTarget describes a single external target to probe
+type ProbeTarget struct {
+	// Protocol is the protocol of a registered Prober: dns, http, tcp or any custom one
+	Protocol string `json:"protocol"`
+	// Target is the host to resolve, the url to get or the <host>:<port> to connect to
+	Target string `json:"target"`
+	// Expect is either allow (default) or deny
+	Expect string `json:"expect,omitempty"`
+	// Timeout overrides the default timeout for the protocol
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+	// Interval overrides the default interval between two probes of the target
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// Options are passed as is to the prober, for protocol specific settings
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// ProbeConfig is the format of the file passed with --probe-config
+type ProbeConfig struct {
+	Probes []ProbeTarget `json:"probes"`
+}
+
+// probeTargets holds all the external targets to probe, it is only written at startup
+var probeTargets []ProbeTarget
+
+// LoadProbeTargets builds the list of external targets to probe from the
+// --host-to-resolve, --tcp-targets and --http-targets flags and the optional probe config file
+func LoadProbeTargets() error {
+	targets := []ProbeTarget{}
+	for _, host := range GoldpingerConfig.DnsHosts {
+		targets = append(targets, ProbeTarget{Protocol: "dns", Target: host})
+	}
+	for _, host := range GoldpingerConfig.HTTPTargets {
+		targets = append(targets, ProbeTarget{Protocol: "http", Target: host})
+	}
+	for _, host := range GoldpingerConfig.TCPTargets {
+		targets = append(targets, ProbeTarget{Protocol: "tcp", Target: host})
+	}
+
+	if GoldpingerConfig.ProbeConfigPath != "" {
+		data, err := os.ReadFile(GoldpingerConfig.ProbeConfigPath)
+		if err != nil {
+			return fmt.Errorf("could not read probe config: %w", err)
+		}
+		var config ProbeConfig
+		if err := yaml.UnmarshalStrict(data, &config); err != nil {
+			return fmt.Errorf("could not parse probe config: %w", err)
+		}
+		targets = append(targets, config.Probes...)
+	}
+
+	for i := range targets {
+		target := &targets[i]
+		if target.Target == "" {
+			return fmt.Errorf("probe %d has no target", i)
+		}
+		if _, ok := getProber(target.Protocol); !ok {
+			return fmt.Errorf("unknown protocol %q for probe target %s, known protocols are %v",
+				target.Protocol, target.Target, getProberProtocols())
+		}
+		switch target.Expect {
+		case "":
+			target.Expect = ExpectAllow
+		case ExpectAllow, ExpectDeny:
+		default:
+			return fmt.Errorf("unknown expectation %q for probe target %s", target.Expect, target.Target)
+		}
+	}
+	probeTargets = targets
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+)
+
+// latestProbeResults holds the latest result of each probe target, by index in probeTargets
+var latestProbeResults = make(map[int]models.ProbeResult)
+
+// latestProbeResultsMux controls concurrent access to latestProbeResults
+var latestProbeResultsMux = sync.Mutex{}
+
+// probeSchedulerRunning is true once the targets are probed in the background
+var probeSchedulerRunning atomic.Bool
+
+// getProbeInterval returns the interval between two probes of the given target
+func getProbeInterval(target ProbeTarget) time.Duration {
+	if target.Interval.Duration > 0 {
+		return target.Interval.Duration
+	}
+	if GoldpingerConfig.ProbeInterval > 0 {
+		return GoldpingerConfig.ProbeInterval
+	}
+	return time.Duration(GoldpingerConfig.RefreshInterval) * time.Second
+}
+
+// startProbeScheduler starts a goroutine per external target, probing it on its own schedule.
+// Like the pingers, the goroutines are started with a spread to prevent a thundering herd
+func startProbeScheduler() {
+	if len(probeTargets) == 0 {
+		return
+	}
+	refreshPeriod := time.Duration(GoldpingerConfig.RefreshInterval) * time.Second
+	waitBetweenTargets := refreshPeriod / time.Duration(len(probeTargets))
+
+	zap.L().Info(
+		"Starting probe scheduler",
+		zap.Int("numTargets", len(probeTargets)),
+		zap.Duration("waitPeriod", waitBetweenTargets),
+		zap.Float64("JitterFactor", GoldpingerConfig.JitterFactor),
+	)
+
+	initialWait := time.Duration(0)
+	for index, target := range probeTargets {
+		go probeContinuously(index, target, initialWait)
+		initialWait += waitBetweenTargets
+	}
+	probeSchedulerRunning.Store(true)
+}
+
+// probeContinuously probes the given target with a delay between
+// `interval` and `interval + jitterFactor * interval`, and saves the latest result
+func probeContinuously(index int, target ProbeTarget, initialWait time.Duration) {
+	timer := time.NewTimer(initialWait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		wait.JitterUntil(func() {
+			result := runProbe(context.Background(), target)
+			latestProbeResultsMux.Lock()
+			latestProbeResults[index] = result
+			latestProbeResultsMux.Unlock()
+		}, getProbeInterval(target), GoldpingerConfig.JitterFactor, false, stopProbes)
+	case <-stopProbes:
+		// Do nothing
+	}
+}
+
+// getLatestProbeResults returns the latest result of each target. The targets not probed yet are reported
+// as pending, with an error, so that they aren't mistaken for healthy ones
+func getLatestProbeResults() models.ProbeResults {
+	latestProbeResultsMux.Lock()
+	defer latestProbeResultsMux.Unlock()
+
+	results := make(map[string][]models.ProbeResult)
+	for index, target := range probeTargets {
+		result, ok := latestProbeResults[index]
+		if !ok {
+			result = models.ProbeResult{
+				Protocol: target.Protocol,
+				Expect:   target.Expect,
+				Error:    "not probed yet",
+				Pending:  true,
+			}
+		}
+		results[target.Target] = append(results[target.Target], result)
+	}
+	return results
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Prober checks a single external target. It returns an error if the target is not healthy.
+// The context carries the timeout of the probe
+type Prober interface {
+	Probe(ctx context.Context, target ProbeTarget) error
+}
+
+// ProberFunc turns a function with the right signature into a Prober
+type ProberFunc func(ctx context.Context, target ProbeTarget) error
+
+// Probe calls the function
+func (fn ProberFunc) Probe(ctx context.Context, target ProbeTarget) error {
+	return fn(ctx, target)
+}
+
+// probers holds the registered probers, by protocol
+var probers = make(map[string]Prober)
+
+// probersMux controls concurrent access to probers
+var probersMux = sync.RWMutex{}
+
+// RegisterProber makes a prober available to probe targets of the given protocol,
+// replacing any prober already registered for it.
+// It needs to be called before the probe targets are loaded, typically from an init function
+func RegisterProber(protocol string, prober Prober) {
+	probersMux.Lock()
+	defer probersMux.Unlock()
+	probers[protocol] = prober
+}
+
+// getProber returns the prober registered for the given protocol
+func getProber(protocol string) (Prober, bool) {
+	probersMux.RLock()
+	defer probersMux.RUnlock()
+	prober, ok := probers[protocol]
+	return prober, ok
+}
+
+// getProberProtocols returns the sorted list of protocols with a registered prober
+func getProberProtocols() []string {
+	probersMux.RLock()
+	defer probersMux.RUnlock()
+	protocols := make([]string, 0, len(probers))
+	for protocol := range probers {
+		protocols = append(protocols, protocol)
+	}
+	sort.Strings(protocols)
+	return protocols
+}
+
+// getProbeTimeout returns the timeout to use for the given target
+func getProbeTimeout(target ProbeTarget) time.Duration {
+	if target.Timeout.Duration > 0 {
+		return target.Timeout.Duration
+	}
+	switch target.Protocol {
+	case "dns":
+		return GoldpingerConfig.DnsCheckTimeout
+	case "http":
+		return GoldpingerConfig.HTTPCheckTimeout
+	case "tcp":
+		return GoldpingerConfig.TCPCheckTimeout
+	default:
+		return GoldpingerConfig.ProbeTimeout
+	}
+}
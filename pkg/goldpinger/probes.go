@@ -17,34 +17,66 @@ package goldpinger
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
-	"time"
+	"sync/atomic"
+	"syscall"
 )
 
-func doDNSProbe(addr string, timeout time.Duration) error {
+// errTargetReached marks the errors of the probes that got through to the target, such as an error
+// status answered by an HTTP target. The targets expected to be denied were reachable then
+var errTargetReached = errors.New("target reached")
+
+// isProbeDenied tells whether a probe failed because the connection was denied: it couldn't be
+// established, was refused, or timed out, or the name of the target couldn't be resolved. Any other
+// error means the connection got through
+func isProbeDenied(err error) bool {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	switch {
+	case err == nil, errors.Is(err, errTargetReached):
+		return false
+	case errors.Is(err, syscall.ECONNREFUSED), errors.As(err, &dnsErr), isTimeout(err):
+		return true
+	default:
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+}
+
+func init() {
+	RegisterProber("dns", ProberFunc(doDNSProbe))
+	RegisterProber("http", ProberFunc(doHTTPProbe))
+	RegisterProber("tcp", ProberFunc(doTCPProbe))
+}
+
+func doDNSProbe(ctx context.Context, target ProbeTarget) error {
 	resolver := net.Resolver{}
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-	ips, err := resolver.LookupHost(ctx, addr)
+	ips, err := resolver.LookupHost(ctx, target.Target)
+	if err != nil {
+		return err
+	}
 	if len(ips) == 0 {
-		return fmt.Errorf("%s was resolved to 0 ips", addr)
+		return fmt.Errorf("%s was resolved to 0 ips", target.Target)
 	}
-	return err
+	return nil
 }
 
-func doTCPProbe(addr string, timeout time.Duration) error {
-	conn, err := net.DialTimeout("tcp", addr, timeout)
+func doTCPProbe(ctx context.Context, target ProbeTarget) error {
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", target.Target)
 	if conn != nil {
 		defer conn.Close()
 	}
 	return err
 }
 
-func doHTTPProbe(addr string, timeout time.Duration) error {
-	client := http.Client{Timeout: timeout}
+func doHTTPProbe(ctx context.Context, target ProbeTarget) error {
+	addr := target.Target
+	client := http.Client{}
 	u, err := url.Parse(addr)
 	if err != nil {
 		return err
@@ -59,14 +91,28 @@ func doHTTPProbe(addr string, timeout time.Duration) error {
 			},
 		}
 	}
-	resp, err := client.Get(addr)
+	// tell the timeouts hit once connected apart from the ones hit while connecting
+	var connected atomic.Bool
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) {
+			connected.Store(true)
+		},
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
 	if err != nil {
 		return err
 	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if connected.Load() {
+			return fmt.Errorf("%w: %w", errTargetReached, err)
+		}
+		return err
+	}
 
 	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("%s returned non-200 resp: %d", addr, resp.StatusCode)
+		return fmt.Errorf("%w: %s returned non-200 resp: %d", errTargetReached, addr, resp.StatusCode)
 	}
-	return err
+	return nil
 }
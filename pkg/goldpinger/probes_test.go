@@ -0,0 +1,97 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+)
+
+func TestRunProbeExpectDeny(t *testing.T) {
+	forbidden := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ok" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer forbidden.Close()
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer hanging.Close()
+
+	// a port nothing listens on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := listener.Addr().String()
+	listener.Close()
+
+	tests := []struct {
+		name          string
+		protocol      string
+		target        string
+		wantViolation bool
+	}{
+		{"http answering 200", "http", forbidden.URL + "/ok", true},
+		{"http answering 403", "http", forbidden.URL, true},
+		{"http timing out once connected", "http", hanging.URL, true},
+		{"http refused", "http", "http://" + closedAddr, false},
+		{"tcp connected", "tcp", forbidden.Listener.Addr().String(), true},
+		{"tcp refused", "tcp", closedAddr, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			target := ProbeTarget{Protocol: test.protocol, Target: test.target, Expect: ExpectDeny}
+			target.Timeout.Duration = 200 * time.Millisecond
+			result := runProbe(context.Background(), target)
+			if result.Violation != test.wantViolation {
+				t.Errorf("violation = %v (%s), want %v", result.Violation, result.Error, test.wantViolation)
+			}
+		})
+	}
+}
+
+func TestGetLatestProbeResultsReportsPendingTargets(t *testing.T) {
+	previousTargets := probeTargets
+	defer func() {
+		probeTargets = previousTargets
+		latestProbeResultsMux.Lock()
+		latestProbeResults = make(map[int]models.ProbeResult)
+		latestProbeResultsMux.Unlock()
+	}()
+	probeTargets = []ProbeTarget{
+		{Protocol: "tcp", Target: "db:5432", Expect: ExpectAllow},
+		{Protocol: "http", Target: "http://blocked", Expect: ExpectDeny},
+	}
+	latestProbeResultsMux.Lock()
+	latestProbeResults = map[int]models.ProbeResult{0: {Protocol: "tcp", Expect: ExpectAllow}}
+	latestProbeResultsMux.Unlock()
+
+	results := getLatestProbeResults()
+	if probed := results["db:5432"]; len(probed) != 1 || probed[0].Pending || probed[0].Error != "" {
+		t.Errorf("results of the probed target = %+v, want its latest result", probed)
+	}
+	pending := results["http://blocked"]
+	if len(pending) != 1 || !pending[0].Pending || pending[0].Error == "" || pending[0].Expect != ExpectDeny {
+		t.Errorf("results of the target not probed yet = %+v, want a pending result with an error", pending)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// clientLimiterIdle is how long the rate limiter of a client is kept after its last call
+const clientLimiterIdle = 10 * time.Minute
+
+// clientLimiter is the rate limiter of a single client
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiters holds the global rate limiter and the per-client ones, by client IP
+var rateLimiters = struct {
+	sync.Mutex
+	global    *rate.Limiter
+	clients   map[string]*clientLimiter
+	lastPurge time.Time
+}{clients: make(map[string]*clientLimiter)}
+
+// getClientLimiter returns the rate limiter of a client, dropping the ones of the clients gone idle
+func getClientLimiter(client string, now time.Time) *rate.Limiter {
+	rateLimiters.Lock()
+	defer rateLimiters.Unlock()
+	if now.Sub(rateLimiters.lastPurge) > clientLimiterIdle {
+		for key, l := range rateLimiters.clients {
+			if now.Sub(l.lastSeen) > clientLimiterIdle {
+				delete(rateLimiters.clients, key)
+			}
+		}
+		rateLimiters.lastPurge = now
+	}
+	l, ok := rateLimiters.clients[client]
+	if !ok {
+		l = &clientLimiter{limiter: rate.NewLimiter(rate.Limit(GoldpingerConfig.ClientRateLimit), getBurst(GoldpingerConfig.ClientRateLimit, GoldpingerConfig.ClientRateBurst))}
+		rateLimiters.clients[client] = l
+	}
+	l.lastSeen = now
+	return l.limiter
+}
+
+// getBurst returns the configured burst of a rate limit, or the smallest burst letting calls through
+func getBurst(limit float64, burst int) int {
+	if burst > 0 {
+		return burst
+	}
+	return int(math.Max(1, math.Ceil(limit)))
+}
+
+// reserve reserves a call on a rate limiter, and tells how long to wait before retrying if the call is over the limit
+func reserve(limiter *rate.Limiter, now time.Time) (*rate.Reservation, time.Duration) {
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return nil, time.Second
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return nil, delay
+	}
+	return reservation, 0
+}
+
+// RateLimitMiddleware rejects the calls to the --rate-limit-paths over the global or per-client rate limits,
+// with a 429 status and a Retry-After header
+func RateLimitMiddleware(next http.Handler) http.Handler {
+	if GoldpingerConfig.RateLimit <= 0 && GoldpingerConfig.ClientRateLimit <= 0 {
+		return next
+	}
+	if GoldpingerConfig.RateLimit > 0 {
+		rateLimiters.global = rate.NewLimiter(rate.Limit(GoldpingerConfig.RateLimit), getBurst(GoldpingerConfig.RateLimit, GoldpingerConfig.RateBurst))
+	}
+	zap.L().Info("Added the rate limiting middleware",
+		zap.Float64("rateLimit", GoldpingerConfig.RateLimit),
+		zap.Float64("clientRateLimit", GoldpingerConfig.ClientRateLimit),
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !pathMatches(GoldpingerConfig.RateLimitPaths, r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		now := time.Now()
+		var clientReservation *rate.Reservation
+		if GoldpingerConfig.ClientRateLimit > 0 {
+			client, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				client = r.RemoteAddr
+			}
+			var delay time.Duration
+			clientReservation, delay = reserve(getClientLimiter(client, now), now)
+			if clientReservation == nil {
+				rejectRateLimited(w, r, "client", delay)
+				return
+			}
+		}
+		if rateLimiters.global != nil {
+			if reservation, delay := reserve(rateLimiters.global, now); reservation == nil {
+				if clientReservation != nil {
+					// the call doesn't count against the client's limit
+					clientReservation.CancelAt(now)
+				}
+				rejectRateLimited(w, r, "global", delay)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rejectRateLimited answers a call over a rate limit
+func rejectRateLimited(w http.ResponseWriter, r *http.Request, limit string, delay time.Duration) {
+	CountRateLimited(r.URL.Path, limit)
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+	http.Error(w, "too many requests", http.StatusTooManyRequests)
+}
+
+// fanoutSlots bounds the number of concurrent outbound calls of a fan-out, with --fanout-concurrency
+var fanoutSlots chan struct{}
+
+// fanoutSlotsOnce creates fanoutSlots on first use
+var fanoutSlotsOnce sync.Once
+
+// acquireFanoutSlot waits for a free worker of the outbound fan-out, and returns the function to release it.
+// It fails if the context is done first
+func acquireFanoutSlot(ctx context.Context) (func(), error) {
+	if GoldpingerConfig.FanoutConcurrency <= 0 {
+		return func() {}, nil
+	}
+	fanoutSlotsOnce.Do(func() {
+		fanoutSlots = make(chan struct{}, GoldpingerConfig.FanoutConcurrency)
+	})
+	select {
+	case fanoutSlots <- struct{}{}:
+		return func() { <-fanoutSlots }, nil
+	default:
+	}
+
+	// all the workers are busy: queue the call
+	IncFanoutQueued(1)
+	defer IncFanoutQueued(-1)
+	select {
+	case fanoutSlots <- struct{}{}:
+		return func() { <-fanoutSlots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for a fan-out worker: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// resetRateLimiters sets the rate limits for the duration of a test, and drops the limiters
+func resetRateLimiters(t *testing.T, rateLimit, clientRateLimit float64, rateBurst, clientRateBurst int) {
+	previousConfig := GoldpingerConfig
+	GoldpingerConfig.RateLimit = rateLimit
+	GoldpingerConfig.ClientRateLimit = clientRateLimit
+	GoldpingerConfig.RateBurst = rateBurst
+	GoldpingerConfig.ClientRateBurst = clientRateBurst
+	GoldpingerConfig.RateLimitPaths = []string{"/check_all", "/federation/"}
+	reset := func() {
+		rateLimiters.Lock()
+		rateLimiters.global = nil
+		rateLimiters.clients = make(map[string]*clientLimiter)
+		rateLimiters.lastPurge = time.Time{}
+		rateLimiters.Unlock()
+	}
+	reset()
+	t.Cleanup(func() {
+		GoldpingerConfig = previousConfig
+		reset()
+	})
+}
+
+func TestGetBurst(t *testing.T) {
+	tests := []struct {
+		limit float64
+		burst int
+		want  int
+	}{
+		{10, 0, 10},
+		{2.5, 0, 3},
+		{0.1, 0, 1},
+		{10, 4, 4},
+	}
+	for _, test := range tests {
+		if got := getBurst(test.limit, test.burst); got != test.want {
+			t.Errorf("getBurst(%v, %d) = %d, want %d", test.limit, test.burst, got, test.want)
+		}
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	type call struct {
+		path       string
+		remoteAddr string
+		wantStatus int
+	}
+	tests := []struct {
+		name            string
+		rateLimit       float64
+		clientRateLimit float64
+		rateBurst       int
+		clientRateBurst int
+		calls           []call
+	}{
+		{
+			"global limit",
+			1, 0, 2, 0,
+			[]call{
+				{"/check_all", "10.0.0.1:1000", http.StatusOK},
+				{"/check_all", "10.0.0.2:1000", http.StatusOK},
+				{"/check_all", "10.0.0.3:1000", http.StatusTooManyRequests},
+			},
+		},
+		{
+			"client limit",
+			0, 1, 0, 1,
+			[]call{
+				{"/check_all", "10.0.0.1:1000", http.StatusOK},
+				{"/check_all", "10.0.0.1:2000", http.StatusTooManyRequests},
+				{"/check_all", "10.0.0.2:1000", http.StatusOK},
+			},
+		},
+		{
+			"calls rejected by the global limit don't count against the client's",
+			1, 1, 1, 2,
+			[]call{
+				{"/check_all", "10.0.0.1:1000", http.StatusOK},
+				{"/check_all", "10.0.0.2:1000", http.StatusTooManyRequests},
+				{"/check_all", "10.0.0.2:1000", http.StatusTooManyRequests},
+			},
+		},
+		{
+			"paths not limited",
+			1, 1, 1, 1,
+			[]call{
+				{"/check_all", "10.0.0.1:1000", http.StatusOK},
+				{"/ping", "10.0.0.1:1000", http.StatusOK},
+				{"/federation/heatmap.png", "10.0.0.1:1000", http.StatusTooManyRequests},
+				{"/ping", "10.0.0.1:1000", http.StatusOK},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetRateLimiters(t, test.rateLimit, test.clientRateLimit, test.rateBurst, test.clientRateBurst)
+			handler := RateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			for i, call := range test.calls {
+				req := httptest.NewRequest(http.MethodGet, call.path, nil)
+				req.RemoteAddr = call.remoteAddr
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				if rec.Code != call.wantStatus {
+					t.Errorf("call %d to %s = %d, want %d", i+1, call.path, rec.Code, call.wantStatus)
+				}
+				if rec.Code == http.StatusTooManyRequests && rec.Header().Get("Retry-After") == "" {
+					t.Errorf("call %d to %s has no Retry-After header", i+1, call.path)
+				}
+			}
+		})
+	}
+}
+
+func TestGetClientLimiterDropsIdleClients(t *testing.T) {
+	resetRateLimiters(t, 0, 1, 0, 0)
+	now := time.Now()
+	getClientLimiter("10.0.0.1", now)
+	getClientLimiter("10.0.0.2", now.Add(clientLimiterIdle))
+	getClientLimiter("10.0.0.2", now.Add(2*clientLimiterIdle))
+
+	rateLimiters.Lock()
+	defer rateLimiters.Unlock()
+	if _, ok := rateLimiters.clients["10.0.0.1"]; ok {
+		t.Error("the limiter of an idle client was kept")
+	}
+	if _, ok := rateLimiters.clients["10.0.0.2"]; !ok {
+		t.Error("the limiter of an active client was dropped")
+	}
+}
+
+func TestAcquireFanoutSlot(t *testing.T) {
+	previousConcurrency := GoldpingerConfig.FanoutConcurrency
+	GoldpingerConfig.FanoutConcurrency = 2
+	fanoutSlotsOnce = sync.Once{}
+	defer func() {
+		GoldpingerConfig.FanoutConcurrency = previousConcurrency
+		fanoutSlotsOnce = sync.Once{}
+	}()
+
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		release, err := acquireFanoutSlot(context.Background())
+		if err != nil {
+			t.Fatalf("acquireFanoutSlot() failed with a free worker: %v", err)
+		}
+		releases = append(releases, release)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := acquireFanoutSlot(ctx); err == nil {
+		t.Error("acquireFanoutSlot() succeeded with all the workers busy")
+	}
+
+	acquired := make(chan error)
+	go func() {
+		release, err := acquireFanoutSlot(context.Background())
+		if err == nil {
+			release()
+		}
+		acquired <- err
+	}()
+	releases[0]()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Errorf("acquireFanoutSlot() failed once a worker was released: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("acquireFanoutSlot() didn't get the released worker")
+	}
+	releases[1]()
+}
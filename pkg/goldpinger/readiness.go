@@ -0,0 +1,242 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+	"github.com/go-openapi/strfmt"
+)
+
+// The components reported by /readyz. Discovery is reported for each discovery source, as
+// discovery/<source name>, and for the listing of the nodes, as discovery/nodes
+const (
+	ReadinessDiscovery = "discovery"
+	ReadinessPingers   = "pingers"
+	ReadinessResults   = "results"
+	ReadinessCollector = "collector"
+	ReadinessShutdown  = "shutdown"
+)
+
+// collectorStallFactor is how many refresh intervals the collector can go without ticking before it's
+// considered stalled
+const collectorStallFactor = 3
+
+// nodesDiscovery is the name the listing of the nodes is reported under
+const nodesDiscovery = "nodes"
+
+// discoveryOutcome is when a discovery source last succeeded and failed, and why it failed
+type discoveryOutcome struct {
+	lastSuccess time.Time
+	lastError   time.Time
+	err         error
+}
+
+// readiness holds what /readyz reports on
+var readiness = struct {
+	sync.Mutex
+	// the outcome of each discovery source, by name, and when any of them last succeeded
+	discovery     map[string]*discoveryOutcome
+	lastDiscovery time.Time
+	// when the pinger of each pod pinged started, set once updatePingers started them
+	pingersStarted bool
+	pinged         map[string]time.Time
+	// set once every pod pinged reported a first result, the pods showing up afterwards don't
+	// make the instance unready again
+	firstRoundDone bool
+	// when the collector last ticked
+	lastCollectorTick time.Time
+}{discovery: make(map[string]*discoveryOutcome)}
+
+// recordDiscovery records the outcome of discovering the pods of a source, or of listing the nodes
+func recordDiscovery(name string, err error) {
+	readiness.Lock()
+	defer readiness.Unlock()
+	outcome, ok := readiness.discovery[name]
+	if !ok {
+		outcome = &discoveryOutcome{}
+		readiness.discovery[name] = outcome
+	}
+	if err != nil {
+		outcome.lastError = time.Now()
+		outcome.err = err
+		return
+	}
+	outcome.lastSuccess = time.Now()
+	readiness.lastDiscovery = outcome.lastSuccess
+	SetLastDiscovery(readiness.lastDiscovery)
+}
+
+// getDiscoveryNames returns the names of the discovery sources, along with the listing of the nodes
+// when it's needed
+func getDiscoveryNames() []string {
+	names := make([]string, 0, len(discoverySources)+1)
+	for _, source := range discoverySources {
+		names = append(names, source.Name)
+	}
+	if needsNodes() && GoldpingerConfig.KubernetesClient != nil {
+		names = append(names, nodesDiscovery)
+	}
+	return names
+}
+
+// recordPingers records the pods pinged by the running pingers
+func recordPingers(pingers map[string]*Pinger) {
+	pinged := make(map[string]time.Time, len(pingers))
+	for podName, pinger := range pingers {
+		pinged[podName] = pinger.started
+	}
+	SetActivePingers(len(pinged))
+	readiness.Lock()
+	defer readiness.Unlock()
+	readiness.pingersStarted = true
+	readiness.pinged = pinged
+}
+
+// recordCollectorTick records that the collector is still processing the results, and checks whether
+// the first round of results is done, for it not to depend on /readyz being called at the right time
+func recordCollectorTick() {
+	readiness.Lock()
+	readiness.lastCollectorTick = time.Now()
+	started := readiness.pingersStarted
+	pinged := readiness.pinged
+	readiness.Unlock()
+
+	if started && countMissingResults(pinged) == 0 {
+		recordFirstRound()
+	}
+}
+
+// recordFirstRound records that every pod pinged reported a first result
+func recordFirstRound() {
+	readiness.Lock()
+	defer readiness.Unlock()
+	readiness.firstRoundDone = true
+}
+
+// countMissingResults counts the pods pinged that didn't report a first result yet
+func countMissingResults(pinged map[string]time.Time) int {
+	checkResultsMux.Lock()
+	defer checkResultsMux.Unlock()
+	missing := 0
+	for podName := range pinged {
+		if _, ok := checkResults.PodResults[podName]; !ok {
+			missing++
+		}
+	}
+	return missing
+}
+
+// newComponentStatus returns the status of a component
+func newComponentStatus(ok bool, format string, args ...interface{}) models.ComponentStatus {
+	return models.ComponentStatus{OK: &ok, Message: fmt.Sprintf(format, args...)}
+}
+
+// getDiscoveryStatus tells whether a discovery source succeeded recently enough
+func getDiscoveryStatus(name string, now time.Time) models.ComponentStatus {
+	readiness.Lock()
+	defer readiness.Unlock()
+	outcome, ok := readiness.discovery[name]
+	if !ok {
+		return newComponentStatus(false, "discovery didn't run yet")
+	}
+	if outcome.lastSuccess.IsZero() {
+		return newComponentStatus(false, "discovery never succeeded: %v", outcome.err)
+	}
+	since := now.Sub(outcome.lastSuccess)
+	if outcome.lastError.After(outcome.lastSuccess) {
+		if since > GoldpingerConfig.ReadinessDiscoveryTimeout {
+			return newComponentStatus(false, "discovery failing for %s: %v", since.Round(time.Second), outcome.err)
+		}
+		return newComponentStatus(true, "discovery failing for %s, ready until %s: %v",
+			since.Round(time.Second), GoldpingerConfig.ReadinessDiscoveryTimeout, outcome.err)
+	}
+	return newComponentStatus(true, "last discovery %s ago", since.Round(time.Second))
+}
+
+// getUpdaterStatus tells whether the pingers are running, whether all of them reported a first result,
+// and whether the collector is still processing their results. Once the first round of results is done,
+// the pods showing up afterwards are only reported on, their pingers can take up to a refresh interval to
+// start; /debug/goldpinger reports the ones that stay without a result
+func getUpdaterStatus(now time.Time) (pingers, results, collector models.ComponentStatus) {
+	if GoldpingerConfig.RefreshInterval <= 0 {
+		disabled := newComponentStatus(true, "the updater is disabled")
+		return disabled, disabled, disabled
+	}
+
+	readiness.Lock()
+	started := readiness.pingersStarted
+	pinged := readiness.pinged
+	firstRoundDone := readiness.firstRoundDone
+	lastTick := readiness.lastCollectorTick
+	readiness.Unlock()
+
+	if !started {
+		pingers = newComponentStatus(false, "the pingers didn't start yet")
+		results = newComponentStatus(false, "no results yet")
+	} else {
+		pingers = newComponentStatus(true, "pinging %d pods", len(pinged))
+
+		missing := countMissingResults(pinged)
+		switch {
+		case missing == 0:
+			recordFirstRound()
+			results = newComponentStatus(true, "results for all %d pods", len(pinged))
+		case firstRoundDone:
+			results = newComponentStatus(true, "waiting for the first results of %d new pods", missing)
+		default:
+			results = newComponentStatus(false, "waiting for the first results of %d of %d pods", missing, len(pinged))
+		}
+	}
+
+	stallAfter := collectorStallFactor * time.Duration(GoldpingerConfig.RefreshInterval) * time.Second
+	if lastTick.IsZero() {
+		collector = newComponentStatus(false, "the collector didn't start yet")
+	} else if since := now.Sub(lastTick); since > stallAfter {
+		collector = newComponentStatus(false, "the collector stalled, last tick %s ago", since.Round(time.Second))
+	} else {
+		collector = newComponentStatus(true, "last tick %s ago", since.Round(time.Second))
+	}
+	return pingers, results, collector
+}
+
+// ReadinessCheck reports whether this instance is ready to serve, and the status of each component
+func ReadinessCheck() *models.ReadinessResults {
+	start := time.Now()
+	components := make(map[string]models.ComponentStatus)
+	for _, name := range getDiscoveryNames() {
+		components[ReadinessDiscovery+"/"+name] = getDiscoveryStatus(name, start)
+	}
+	components[ReadinessPingers], components[ReadinessResults], components[ReadinessCollector] = getUpdaterStatus(start)
+	if IsShuttingDown() {
+		components[ReadinessShutdown] = newComponentStatus(false, "shutting down")
+	} else {
+		components[ReadinessShutdown] = newComponentStatus(true, "running")
+	}
+
+	ok := true
+	for _, status := range components {
+		ok = ok && *status.OK
+	}
+	return &models.ReadinessResults{
+		OK:          &ok,
+		Components:  components,
+		DurationNs:  time.Since(start).Nanoseconds(),
+		GeneratedAt: strfmt.DateTime(start),
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+)
+
+// resetReadiness clears what /readyz reports on, and the results it looks at
+func resetReadiness(t *testing.T) {
+	reset := func() {
+		readiness.Lock()
+		readiness.discovery = make(map[string]*discoveryOutcome)
+		readiness.lastDiscovery = time.Time{}
+		readiness.pingersStarted = false
+		readiness.pinged = nil
+		readiness.firstRoundDone = false
+		readiness.lastCollectorTick = time.Time{}
+		readiness.Unlock()
+		checkResultsMux.Lock()
+		checkResults.PodResults = make(map[string]models.PodResult)
+		checkResultsMux.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestGetDiscoveryStatus(t *testing.T) {
+	previousTimeout := GoldpingerConfig.ReadinessDiscoveryTimeout
+	GoldpingerConfig.ReadinessDiscoveryTimeout = time.Minute
+	defer func() { GoldpingerConfig.ReadinessDiscoveryTimeout = previousTimeout }()
+
+	now := time.Now()
+	tests := []struct {
+		name    string
+		outcome *discoveryOutcome
+		wantOK  bool
+	}{
+		{"didn't run yet", nil, false},
+		{"never succeeded", &discoveryOutcome{lastError: now, err: errors.New("boom")}, false},
+		{"succeeded", &discoveryOutcome{lastSuccess: now}, true},
+		{"failing within the timeout", &discoveryOutcome{lastSuccess: now.Add(-30 * time.Second), lastError: now, err: errors.New("boom")}, true},
+		{"failing for too long", &discoveryOutcome{lastSuccess: now.Add(-2 * time.Minute), lastError: now, err: errors.New("boom")}, false},
+		{"recovered", &discoveryOutcome{lastSuccess: now, lastError: now.Add(-time.Hour), err: errors.New("boom")}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetReadiness(t)
+			if test.outcome != nil {
+				readiness.discovery["source"] = test.outcome
+			}
+			// the outcome of the other sources doesn't matter
+			readiness.discovery["other"] = &discoveryOutcome{lastError: now, err: errors.New("boom")}
+			status := getDiscoveryStatus("source", now)
+			if *status.OK != test.wantOK {
+				t.Errorf("getDiscoveryStatus() = %v (%s), want %v", *status.OK, status.Message, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestResultsReadinessLatchesAfterTheFirstRound(t *testing.T) {
+	resetReadiness(t)
+	previousRefreshInterval := GoldpingerConfig.RefreshInterval
+	GoldpingerConfig.RefreshInterval = 30
+	defer func() { GoldpingerConfig.RefreshInterval = previousRefreshInterval }()
+
+	now := time.Now()
+	resultsOK := func() bool {
+		_, results, _ := getUpdaterStatus(now)
+		return *results.OK
+	}
+	recordPingers(map[string]*Pinger{"p1": {started: now}, "p2": {started: now}})
+	if resultsOK() {
+		t.Fatal("results ready before any result")
+	}
+
+	checkResults.PodResults["p1"] = models.PodResult{}
+	if resultsOK() {
+		t.Fatal("results ready before the first round is done")
+	}
+
+	checkResults.PodResults["p2"] = models.PodResult{}
+	recordCollectorTick()
+	if !resultsOK() {
+		t.Fatal("results not ready once the first round is done")
+	}
+
+	// a new peer joins
+	recordPingers(map[string]*Pinger{"p1": {started: now}, "p2": {started: now}, "p3": {started: now}})
+	if !resultsOK() {
+		t.Fatal("results not ready any more once a new peer joined")
+	}
+}
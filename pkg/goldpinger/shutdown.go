@@ -0,0 +1,251 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bloomberg/goldpinger/v3/pkg/client/operations"
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+	"github.com/go-openapi/strfmt"
+	"go.uber.org/zap"
+)
+
+// shuttingDown is true once the shutdown started
+var shuttingDown atomic.Bool
+
+// updaterRunning is true once the pingers and the collector are started
+var updaterRunning atomic.Bool
+
+var (
+	// stopUpdater is closed to make updatePingers stop all the pingers
+	stopUpdater = make(chan struct{})
+	// updaterStopped is closed once all the pingers are stopped
+	updaterStopped = make(chan struct{})
+	// stopCollector is closed to stop collectResults
+	stopCollector = make(chan struct{})
+	// stopProbes is closed to stop the probe scheduler
+	stopProbes = make(chan struct{})
+)
+
+// pingersWG waits for the pinger goroutines
+var pingersWG sync.WaitGroup
+
+// shutdownRejectedPaths are the paths rejected once the shutdown started, since the calls they
+// make could outlive this instance
+var shutdownRejectedPaths = []string{
+	"/check",
+	"/check_all",
+	"/cluster_health",
+	"/zone_matrix",
+	"/federation",
+	"/heatmap.png",
+	"/federation/heatmap.png",
+}
+
+// IsShuttingDown tells whether the shutdown started
+func IsShuttingDown() bool {
+	return shuttingDown.Load()
+}
+
+// ShutdownMiddleware rejects the new checks with a 503 status once the shutdown started
+func ShutdownMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if IsShuttingDown() && pathMatches(shutdownRejectedPaths, r.URL.Path) {
+			CountCall("rejected", "shutdown")
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Shutdown stops this instance in order: it stops accepting new checks, stops the pingers and the
+// probes, announces its departure to its peers with --announce-departure, and stops the collector
+// and the gRPC server. It gives up waiting after --shutdown-timeout
+func Shutdown() {
+	if !shuttingDown.CompareAndSwap(false, true) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), GoldpingerConfig.ShutdownTimeout)
+	defer cancel()
+	logger := zap.L().With(zap.String("op", "shutdown"))
+	logger.Info("Shutting down")
+
+	// the pingers send their last results to the collector, so it's stopped after them
+	if updaterRunning.Load() {
+		close(stopUpdater)
+		select {
+		case <-updaterStopped:
+			logger.Info("Stopped the pingers")
+		case <-ctx.Done():
+			logger.Warn("Timed out stopping the pingers")
+		}
+	}
+	if probeSchedulerRunning.Load() {
+		close(stopProbes)
+	}
+
+	if GoldpingerConfig.AnnounceDeparture {
+		announceDeparture(ctx)
+	}
+
+	if updaterRunning.Load() {
+		close(stopCollector)
+	}
+	if err := stopGRPCServer(ctx); err != nil {
+		logger.Warn("Error stopping the gRPC server", zap.Error(err))
+	}
+	logger.Info("Done shutting down, draining the API")
+}
+
+// getLocalPod returns this instance, as discovered
+func getLocalPod(pods map[string]*GoldpingerPod) *GoldpingerPod {
+	if pod, ok := pods[getLocalPodKey()]; ok {
+		return pod
+	}
+	if pod, ok := pods[GoldpingerConfig.Hostname]; ok {
+		return pod
+	}
+	return &GoldpingerPod{
+		Name:  GoldpingerConfig.Hostname,
+		PodIP: GoldpingerConfig.PodIP,
+	}
+}
+
+// announceDeparture tells all the other instances that this one is shutting down
+func announceDeparture(ctx context.Context) {
+	pods := GetAllPods()
+	local := getLocalPod(pods)
+	if local.PodIP == "" {
+		zap.L().Warn("Not announcing the departure, the pod IP is unknown")
+		return
+	}
+	notice := &models.DepartureNotice{
+		Name:   local.Name,
+		PodIP:  &local.PodIP,
+		HostIP: local.HostIP,
+	}
+
+	wg := sync.WaitGroup{}
+	for _, pod := range pods {
+		if pod.Role != RolePeer || pod.PodIP == local.PodIP {
+			continue
+		}
+		wg.Add(1)
+		go func(pod *GoldpingerPod) {
+			defer wg.Done()
+			logger := zap.L().With(
+				zap.String("op", "depart"),
+				zap.String("name", pod.Name),
+				zap.String("hostIP", pod.HostIP),
+				zap.String("podIP", pod.PodIP),
+			)
+			release, err := acquireFanoutSlot(ctx)
+			if err != nil {
+				logger.Warn("Couldn't announce the departure", zap.Error(err))
+				CountError("depart")
+				return
+			}
+			defer release()
+
+			CountCall("made", "depart")
+			client, err := getClient(pickPodHostIP(pod.PodIP, pod.HostIP), getPodPort(pod), getPeerIdentity(pod))
+			if err == nil {
+				departCtx, cancel := context.WithTimeout(ctx, GoldpingerConfig.CheckTimeout)
+				defer cancel()
+				_, err = client.Operations.Depart(operations.NewDepartParamsWithContext(departCtx).WithNotice(notice))
+			}
+			if err != nil {
+				logger.Warn("Couldn't announce the departure", zap.Error(err))
+				countPeerCallError("depart", err)
+			}
+		}(pod)
+	}
+	wg.Wait()
+	zap.L().Info("Announced the departure")
+}
+
+// departures holds when the departure of the peers that announced it expires, by pod IP
+var departures = struct {
+	sync.Mutex
+	expires map[string]time.Time
+}{expires: make(map[string]time.Time)}
+
+// isDepartureFromPeer tells whether a departure notice sent from the given address may be trusted. With
+// authentication, the notice comes from an authenticated caller. Without it, only the departing instance
+// may announce its departure, from its own pod IP
+func isDepartureFromPeer(notice *models.DepartureNotice, remoteAddr string) bool {
+	if len(GoldpingerConfig.AuthModes) > 0 {
+		return true
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	sourceIP := net.ParseIP(host)
+	return sourceIP != nil && sourceIP.Equal(net.ParseIP(*notice.PodIP))
+}
+
+// RecordDeparture records that a peer is shutting down, for --departure-ttl. The notice is rejected,
+// with OK set to false, unless it can be trusted
+func RecordDeparture(notice *models.DepartureNotice, remoteAddr string) *models.DepartResults {
+	if !isDepartureFromPeer(notice, remoteAddr) {
+		zap.L().Warn("Rejected a departure notice not sent by the departing peer",
+			zap.String("name", notice.Name),
+			zap.String("podIP", *notice.PodIP),
+			zap.String("remoteAddr", remoteAddr),
+		)
+		CountError("depart")
+		OK := false
+		return &models.DepartResults{OK: &OK}
+	}
+
+	expires := time.Now().Add(GoldpingerConfig.DepartureTTL)
+	departures.Lock()
+	departures.expires[*notice.PodIP] = expires
+	departures.Unlock()
+
+	zap.L().Info("Peer departed",
+		zap.String("name", notice.Name),
+		zap.String("podIP", *notice.PodIP),
+		zap.String("hostIP", notice.HostIP),
+	)
+	OK := true
+	return &models.DepartResults{
+		OK:        &OK,
+		ExpiresAt: strfmt.DateTime(expires),
+	}
+}
+
+// isDeparted tells whether the peer with the given pod IP announced that it is shutting down,
+// dropping the expired departures
+func isDeparted(podIP string) bool {
+	departures.Lock()
+	defer departures.Unlock()
+	now := time.Now()
+	for ip, expires := range departures.expires {
+		if now.After(expires) {
+			delete(departures.expires, ip)
+		}
+	}
+	_, ok := departures.expires[podIP]
+	return ok
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"testing"
+
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+)
+
+func TestIsDepartureFromPeer(t *testing.T) {
+	previousAuthModes := GoldpingerConfig.AuthModes
+	defer func() { GoldpingerConfig.AuthModes = previousAuthModes }()
+
+	podIP := "10.0.0.1"
+	notice := &models.DepartureNotice{Name: "p1", PodIP: &podIP}
+	tests := []struct {
+		name       string
+		authModes  []string
+		remoteAddr string
+		want       bool
+	}{
+		{"from the departing pod", nil, "10.0.0.1:41234", true},
+		{"from another pod", nil, "10.0.0.2:41234", false},
+		{"from an IPv4-mapped address", nil, "[::ffff:10.0.0.1]:41234", true},
+		{"from an invalid address", nil, "somewhere", false},
+		{"from another pod with authentication", []string{AuthModeToken}, "10.0.0.2:41234", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			GoldpingerConfig.AuthModes = test.authModes
+			if got := isDepartureFromPeer(notice, test.remoteAddr); got != test.want {
+				t.Errorf("isDepartureFromPeer() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
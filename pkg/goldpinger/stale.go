@@ -0,0 +1,64 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"time"
+
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+)
+
+// staleFactor is how many refresh intervals a peer can go without a result before it's stale,
+// unless --stale-result-threshold is set
+const staleFactor = 3
+
+// getStaleThreshold returns how old a ping result can get before it's stale
+func getStaleThreshold() time.Duration {
+	if GoldpingerConfig.StaleResultThreshold > 0 {
+		return GoldpingerConfig.StaleResultThreshold
+	}
+	return staleFactor * time.Duration(GoldpingerConfig.RefreshInterval) * time.Second
+}
+
+// stampPodResult sets the age and the status of a ping result, downgrading it to unknown once it's stale
+func stampPodResult(result models.PodResult, now time.Time) models.PodResult {
+	age := now.Sub(time.Time(result.PingTime))
+	result.AgeMs = age.Milliseconds()
+	if threshold := getStaleThreshold(); threshold > 0 && age > threshold {
+		// an old success says nothing about the peer now
+		OK := false
+		result.OK = &OK
+		result.Status = models.PodResultStatusUnknown
+	} else if result.OK != nil && *result.OK {
+		result.Status = models.PodResultStatusOk
+	} else {
+		result.Status = models.PodResultStatusFailed
+	}
+	return result
+}
+
+// getCheckAllPodStatus returns the status of the check of a peer: failed if the peer couldn't be checked,
+// unknown if some of the results it reported are stale, ok otherwise
+func getCheckAllPodStatus(result models.CheckAllPodResult) string {
+	if result.OK == nil || !*result.OK || result.Response == nil {
+		return models.CheckAllPodResultStatusFailed
+	}
+	for _, podResult := range result.Response.PodResults {
+		if podResult.Status == models.PodResultStatusUnknown {
+			return models.CheckAllPodResultStatusUnknown
+		}
+	}
+	return models.CheckAllPodResultStatusOk
+}
@@ -16,6 +16,7 @@ package goldpinger
 
 import (
 	"context"
+	"net"
 	"time"
 
 	"github.com/bloomberg/goldpinger/v3/pkg/models"
@@ -40,7 +41,7 @@ var (
 	goldpingerNodesHealthGauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "goldpinger_nodes_health_total",
-			Help: "Number of nodes seen as healthy/unhealthy from this instance's POV",
+			Help: "Number of nodes seen as healthy/unhealthy/unknown/maintenance/departed from this instance's POV",
 		},
 		[]string{
 			"goldpinger_instance",
@@ -69,6 +70,20 @@ var (
 			"call_type",
 			"host_ip",
 			"pod_ip",
+			"source",
+		},
+	)
+
+	goldpingerResponseTimeZonesHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "goldpinger_zone_response_time_s",
+			Help:    "Histogram of response times from other hosts, by source and destination zone",
+			Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{
+			"goldpinger_instance",
+			"source_zone",
+			"destination_zone",
 		},
 	)
 
@@ -91,6 +106,7 @@ var (
 		[]string{
 			"goldpinger_instance",
 			"type",
+			"reason",
 		},
 	)
 	goldpingerDnsErrorsCounter = prometheus.NewCounterVec(
@@ -123,6 +139,170 @@ var (
 			"host",
 		},
 	)
+	goldpingerProbeDurationHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "goldpinger_probe_duration_seconds",
+			Help:    "Histogram of the durations of probes to external targets",
+			Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{
+			"goldpinger_instance",
+			"protocol",
+			"target",
+		},
+	)
+	goldpingerProbeSuccessGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "goldpinger_probe_success",
+			Help: "1 if the last probe of an external target had the expected outcome, 0 otherwise",
+		},
+		[]string{
+			"goldpinger_instance",
+			"protocol",
+			"target",
+		},
+	)
+	goldpingerProbeErrorsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "goldpinger_probe_errors_total",
+			Help: "Statistics of probe errors per instance, for all protocols",
+		},
+		[]string{
+			"goldpinger_instance",
+			"protocol",
+			"host",
+		},
+	)
+	goldpingerProbeViolationsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "goldpinger_probe_violations_total",
+			Help: "Statistics of probes to targets expected to be denied that were allowed",
+		},
+		[]string{
+			"goldpinger_instance",
+			"protocol",
+			"host",
+		},
+	)
+	goldpingerUnexpectedSnatGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "goldpinger_peers_unexpected_snat",
+			Help: "1 if the peer saw pings come from another IP than this pod's IP, 0 otherwise",
+		},
+		[]string{
+			"goldpinger_instance",
+			"host_ip",
+			"pod_ip",
+		},
+	)
+	goldpingerPeerClockOffsetGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "goldpinger_peer_clock_offset_seconds",
+			Help: "Estimated offset of the peers' clocks compared to this instance's clock",
+		},
+		[]string{
+			"goldpinger_instance",
+			"host_ip",
+			"pod_ip",
+		},
+	)
+	goldpingerAuthRequestsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "goldpinger_auth_requests_total",
+			Help: "Statistics of the authentication of the API calls received, outside of the open paths",
+		},
+		[]string{
+			"goldpinger_instance",
+			"mode",
+			"result",
+		},
+	)
+	goldpingerRateLimitedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "goldpinger_rate_limited_total",
+			Help: "Statistics of API calls rejected for being over the global or per-client rate limits",
+		},
+		[]string{
+			"goldpinger_instance",
+			"path",
+			"limit",
+		},
+	)
+	goldpingerFanoutQueuedGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "goldpinger_fanout_queued_calls",
+			Help: "Number of outbound calls waiting for a free worker, with --fanout-concurrency",
+		},
+		[]string{
+			"goldpinger_instance",
+		},
+	)
+	goldpingerActivePingersGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "goldpinger_active_pingers",
+			Help: "Number of peers being pinged",
+		},
+		[]string{
+			"goldpinger_instance",
+		},
+	)
+	goldpingerPingerGoroutinesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "goldpinger_pinger_goroutines",
+			Help: "Number of pinger goroutines still running, including the ones being stopped",
+		},
+		[]string{
+			"goldpinger_instance",
+		},
+	)
+	goldpingerResultsChannelDepthGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "goldpinger_results_channel_depth",
+			Help: "Number of ping results waiting for the collector",
+		},
+		[]string{
+			"goldpinger_instance",
+		},
+	)
+	goldpingerLastDiscoveryGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "goldpinger_last_discovery_timestamp_seconds",
+			Help: "Unix time of the last successful discovery of the peers",
+		},
+		[]string{
+			"goldpinger_instance",
+		},
+	)
+	goldpingerPeerLastResultGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "goldpinger_peer_last_result_timestamp_seconds",
+			Help: "Unix time of the last ping result of each peer received by the collector",
+		},
+		[]string{
+			"goldpinger_instance",
+			"host_ip",
+			"pod_ip",
+		},
+	)
+	goldpingerStalePeersGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "goldpinger_stale_peers",
+			Help: "Number of peers with no ping result for longer than the stale result threshold",
+		},
+		[]string{
+			"goldpinger_instance",
+		},
+	)
+	goldpingerPeerStatesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "goldpinger_peers_state",
+			Help: "Number of peers in each state (up, degraded, down, flapping) from this instance's POV",
+		},
+		[]string{
+			"goldpinger_instance",
+			"state",
+		},
+	)
 	bootTime = time.Now()
 )
 
@@ -131,18 +311,47 @@ func init() {
 	prometheus.MustRegister(goldpingerNodesHealthGauge)
 	prometheus.MustRegister(goldpingerClusterHealthGauge)
 	prometheus.MustRegister(goldpingerResponseTimePeersHistogram)
+	prometheus.MustRegister(goldpingerResponseTimeZonesHistogram)
 	prometheus.MustRegister(goldpingerResponseTimeKubernetesHistogram)
 	prometheus.MustRegister(goldpingerErrorsCounter)
 	prometheus.MustRegister(goldpingerDnsErrorsCounter)
 	prometheus.MustRegister(goldPingerHttpErrorsCounter)
 	prometheus.MustRegister(goldPingerTcpErrorsCounter)
+	prometheus.MustRegister(goldpingerProbeDurationHistogram)
+	prometheus.MustRegister(goldpingerProbeSuccessGauge)
+	prometheus.MustRegister(goldpingerProbeErrorsCounter)
+	prometheus.MustRegister(goldpingerProbeViolationsCounter)
+	prometheus.MustRegister(goldpingerUnexpectedSnatGauge)
+	prometheus.MustRegister(goldpingerPeerClockOffsetGauge)
+	prometheus.MustRegister(goldpingerAuthRequestsCounter)
+	prometheus.MustRegister(goldpingerRateLimitedCounter)
+	prometheus.MustRegister(goldpingerFanoutQueuedGauge)
+	prometheus.MustRegister(goldpingerActivePingersGauge)
+	prometheus.MustRegister(goldpingerPingerGoroutinesGauge)
+	prometheus.MustRegister(goldpingerResultsChannelDepthGauge)
+	prometheus.MustRegister(goldpingerLastDiscoveryGauge)
+	prometheus.MustRegister(goldpingerPeerLastResultGauge)
+	prometheus.MustRegister(goldpingerStalePeersGauge)
+	prometheus.MustRegister(goldpingerPeerStatesGauge)
 	zap.L().Info("Metrics setup - see /metrics")
 }
 
-func GetStats(ctx context.Context) *models.PingResults {
+// GetStats returns the results of a ping, including the address the caller was seen coming from
+func GetStats(ctx context.Context, remoteAddr string) *models.PingResults {
+	sourceIP, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		sourceIP = remoteAddr
+	}
+	nodeName := GoldpingerConfig.NodeName
+	if nodeName == "" {
+		nodeName = GoldpingerConfig.Hostname
+	}
 	// GetStats no longer populates the received and made calls - use metrics for that instead
 	return &models.PingResults{
-		BootTime: strfmt.DateTime(bootTime),
+		BootTime:   strfmt.DateTime(bootTime),
+		ServerTime: strfmt.DateTime(time.Now()),
+		SourceIP:   sourceIP,
+		NodeName:   nodeName,
 	}
 }
 
@@ -155,8 +364,8 @@ func CountCall(group string, call string) {
 	).Inc()
 }
 
-// counts healthy and unhealthy nodes
-func CountHealthyUnhealthyNodes(healthy, unhealthy float64) {
+// counts healthy, unhealthy, unknown, in maintenance and departed nodes
+func CountHealthyUnhealthyNodes(healthy, unhealthy, unknown, maintenance, departed float64) {
 	goldpingerNodesHealthGauge.WithLabelValues(
 		GoldpingerConfig.Hostname,
 		"healthy",
@@ -165,6 +374,28 @@ func CountHealthyUnhealthyNodes(healthy, unhealthy float64) {
 		GoldpingerConfig.Hostname,
 		"unhealthy",
 	).Set(unhealthy)
+	goldpingerNodesHealthGauge.WithLabelValues(
+		GoldpingerConfig.Hostname,
+		"unknown",
+	).Set(unknown)
+	goldpingerNodesHealthGauge.WithLabelValues(
+		GoldpingerConfig.Hostname,
+		"maintenance",
+	).Set(maintenance)
+	goldpingerNodesHealthGauge.WithLabelValues(
+		GoldpingerConfig.Hostname,
+		"departed",
+	).Set(departed)
+}
+
+// SetPeerStates sets the number of peers in each state
+func SetPeerStates(counts map[string]float64) {
+	for state, count := range counts {
+		goldpingerPeerStatesGauge.WithLabelValues(
+			GoldpingerConfig.Hostname,
+			state,
+		).Set(count)
+	}
 }
 
 // SetClusterHealth sets the cluster health gauge to 1 (healthy) or 0 (unhealthy)
@@ -180,12 +411,96 @@ func SetClusterHealth(healthy bool) {
 
 // counts instances of various errors
 func CountError(errorType string) {
+	CountErrorWithReason(errorType, "")
+}
+
+// counts instances of errors calling peers, by reason
+func CountErrorWithReason(errorType, reason string) {
 	goldpingerErrorsCounter.WithLabelValues(
 		GoldpingerConfig.Hostname,
 		errorType,
+		reason,
 	).Inc()
 }
 
+// counts the authentication results of API calls
+func CountAuthRequest(mode, result string) {
+	goldpingerAuthRequestsCounter.WithLabelValues(
+		GoldpingerConfig.Hostname,
+		mode,
+		result,
+	).Inc()
+}
+
+// counts the API calls rejected by a rate limit
+func CountRateLimited(path, limit string) {
+	goldpingerRateLimitedCounter.WithLabelValues(
+		GoldpingerConfig.Hostname,
+		path,
+		limit,
+	).Inc()
+}
+
+// IncFanoutQueued adds delta to the number of outbound calls waiting for a worker
+func IncFanoutQueued(delta float64) {
+	goldpingerFanoutQueuedGauge.WithLabelValues(
+		GoldpingerConfig.Hostname,
+	).Add(delta)
+}
+
+// SetActivePingers sets the number of peers being pinged
+func SetActivePingers(count int) {
+	goldpingerActivePingersGauge.WithLabelValues(
+		GoldpingerConfig.Hostname,
+	).Set(float64(count))
+}
+
+// SetPingerGoroutines sets the number of pinger goroutines still running
+func SetPingerGoroutines(count int64) {
+	goldpingerPingerGoroutinesGauge.WithLabelValues(
+		GoldpingerConfig.Hostname,
+	).Set(float64(count))
+}
+
+// SetResultsChannelDepth sets the number of ping results waiting for the collector
+func SetResultsChannelDepth(depth int) {
+	goldpingerResultsChannelDepthGauge.WithLabelValues(
+		GoldpingerConfig.Hostname,
+	).Set(float64(depth))
+}
+
+// SetLastDiscovery sets the time of the last successful discovery
+func SetLastDiscovery(t time.Time) {
+	goldpingerLastDiscoveryGauge.WithLabelValues(
+		GoldpingerConfig.Hostname,
+	).Set(float64(t.Unix()))
+}
+
+// SetPeerLastResult sets the time of the last ping result of a peer
+func SetPeerLastResult(hostIP, podIP string, t time.Time) {
+	goldpingerPeerLastResultGauge.WithLabelValues(
+		GoldpingerConfig.Hostname,
+		hostIP,
+		podIP,
+	).Set(float64(t.Unix()))
+}
+
+// DeletePeerLastResult drops the time of the last ping result of a peer no longer pinged
+func DeletePeerLastResult(hostIP, podIP string) {
+	goldpingerPeerLastResultGauge.DeleteLabelValues(
+		GoldpingerConfig.Hostname,
+		hostIP,
+		podIP,
+	)
+}
+
+// SetStalePeers sets the number of peers with no recent ping result
+func SetStalePeers(count int) {
+	goldpingerStalePeersGauge.WithLabelValues(
+		GoldpingerConfig.Hostname,
+	).Set(float64(count))
+}
+
 // counts instances of dns errors
 func CountDnsError(host string) {
 	goldpingerDnsErrorsCounter.WithLabelValues(
@@ -210,6 +525,101 @@ func CountHttpError(host string) {
 	).Inc()
 }
 
+// ObserveProbe records the duration and the outcome of a probe to an external target
+func ObserveProbe(protocol, target string, duration time.Duration, success bool) {
+	goldpingerProbeDurationHistogram.WithLabelValues(
+		GoldpingerConfig.Hostname,
+		protocol,
+		target,
+	).Observe(duration.Seconds())
+
+	value := 1.0
+	if !success {
+		value = 0
+	}
+	goldpingerProbeSuccessGauge.WithLabelValues(
+		GoldpingerConfig.Hostname,
+		protocol,
+		target,
+	).Set(value)
+}
+
+// CountProbeError counts instances of probe errors, and keeps the protocol specific
+// counters of the built-in probers up to date
+func CountProbeError(protocol, host string) {
+	switch protocol {
+	case "dns":
+		CountDnsError(host)
+	case "http":
+		CountHttpError(host)
+	case "tcp":
+		CountTcpError(host)
+	}
+	goldpingerProbeErrorsCounter.WithLabelValues(
+		GoldpingerConfig.Hostname,
+		protocol,
+		host,
+	).Inc()
+}
+
+// CountProbeViolation counts probes to targets expected to be denied that were allowed
+func CountProbeViolation(protocol, host string) {
+	goldpingerProbeViolationsCounter.WithLabelValues(
+		GoldpingerConfig.Hostname,
+		protocol,
+		host,
+	).Inc()
+}
+
+// SetUnexpectedSnat sets the unexpected SNAT gauge of a peer to 1 (detected) or 0 (not detected)
+func SetUnexpectedSnat(hostIP, podIP string, detected bool) {
+	value := 0.0
+	if detected {
+		value = 1
+	}
+	goldpingerUnexpectedSnatGauge.WithLabelValues(
+		GoldpingerConfig.Hostname,
+		hostIP,
+		podIP,
+	).Set(value)
+}
+
+// DeleteUnexpectedSnat drops the unexpected SNAT gauge of a peer no longer pinged
+func DeleteUnexpectedSnat(hostIP, podIP string) {
+	goldpingerUnexpectedSnatGauge.DeleteLabelValues(
+		GoldpingerConfig.Hostname,
+		hostIP,
+		podIP,
+	)
+}
+
+// SetPeerClockOffset sets the estimated clock offset of a peer
+func SetPeerClockOffset(hostIP, podIP string, offset time.Duration) {
+	goldpingerPeerClockOffsetGauge.WithLabelValues(
+		GoldpingerConfig.Hostname,
+		hostIP,
+		podIP,
+	).Set(offset.Seconds())
+}
+
+// DeletePeerClockOffset drops the estimated clock offset of a peer no longer pinged
+func DeletePeerClockOffset(hostIP, podIP string) {
+	goldpingerPeerClockOffsetGauge.DeleteLabelValues(
+		GoldpingerConfig.Hostname,
+		hostIP,
+		podIP,
+	)
+}
+
+// ObserveZoneResponseTime records the response time of a ping between two zones
+func ObserveZoneResponseTime(sourceZone, destinationZone string, responseTime time.Duration) {
+	goldpingerResponseTimeZonesHistogram.WithLabelValues(
+		GoldpingerConfig.Hostname,
+		zoneOrUnknown(sourceZone),
+		zoneOrUnknown(destinationZone),
+	).Observe(responseTime.Seconds())
+}
+
 // returns a timer for easy observing of the durations of calls to kubernetes API
 func GetLabeledKubernetesCallsTimer() *prometheus.Timer {
 	return prometheus.NewTimer(
@@ -220,13 +630,14 @@ func GetLabeledKubernetesCallsTimer() *prometheus.Timer {
 }
 
 // returns a timer for easy observing of the duration of calls to peers
-func GetLabeledPeersCallsTimer(callType, hostIP, podIP string) *prometheus.Timer {
+func GetLabeledPeersCallsTimer(callType, hostIP, podIP, source string) *prometheus.Timer {
 	return prometheus.NewTimer(
 		goldpingerResponseTimePeersHistogram.WithLabelValues(
 			GoldpingerConfig.Hostname,
 			callType,
 			hostIP,
 			podIP,
+			source,
 		),
 	)
 }
@@ -0,0 +1,133 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	zoneLabel         = "topology.kubernetes.io/zone"
+	regionLabel       = "topology.kubernetes.io/region"
+	legacyZoneLabel   = "failure-domain.beta.kubernetes.io/zone"
+	legacyRegionLabel = "failure-domain.beta.kubernetes.io/region"
+
+	// unknownZone is used for the zone and region of nodes that don't have the labels
+	unknownZone = "unknown"
+)
+
+// nodeTopology is where a node lives in the cluster
+type nodeTopology struct {
+	Zone   string
+	Region string
+	Labels map[string]string // Labels are the node labels selected with --node-labels
+}
+
+// localTopology is where this goldpinger instance lives, as of the last discovery
+var localTopology nodeTopology
+
+// localTopologyMux controls concurrent access to localTopology
+var localTopologyMux = sync.RWMutex{}
+
+// getLabel returns the value of the first of the given labels that is set
+func getLabel(labels map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := labels[key]; ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// getNodeTopology extracts the zone, region and selected extra labels of a node
+func getNodeTopology(node v1.Node) nodeTopology {
+	topology := nodeTopology{
+		Zone:   getLabel(node.Labels, zoneLabel, legacyZoneLabel),
+		Region: getLabel(node.Labels, regionLabel, legacyRegionLabel),
+	}
+	for _, key := range getNodeLabelKeys() {
+		if value, ok := node.Labels[key]; ok {
+			if topology.Labels == nil {
+				topology.Labels = make(map[string]string)
+			}
+			topology.Labels[key] = value
+		}
+	}
+	return topology
+}
+
+// getNodeLabelKeys returns the extra node labels to collect: the ones selected with --node-labels,
+// and the --topology-key of the topology ping strategy
+func getNodeLabelKeys() []string {
+	keys := GoldpingerConfig.NodeLabels
+	if GoldpingerConfig.PingStrategy == PingStrategyTopology {
+		switch GoldpingerConfig.TopologyKey {
+		case zoneLabel, regionLabel:
+		default:
+			keys = append(keys[:len(keys):len(keys)], GoldpingerConfig.TopologyKey)
+		}
+	}
+	return keys
+}
+
+// listNodes lists the nodes of the cluster
+func listNodes() ([]v1.Node, error) {
+	if GoldpingerConfig.KubernetesClient == nil {
+		return nil, nil
+	}
+	timer := GetLabeledKubernetesCallsTimer()
+	nodes, err := GoldpingerConfig.KubernetesClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		zap.L().Error("Error listing nodes", zap.Error(err))
+		CountError("kubernetes_api")
+		return nil, err
+	}
+	timer.ObserveDuration()
+	return nodes.Items, nil
+}
+
+// getNodesTopology returns a mapping from node name to topology
+func getNodesTopology(nodes []v1.Node) map[string]nodeTopology {
+	topology := make(map[string]nodeTopology, len(nodes))
+	for _, node := range nodes {
+		topology[node.Name] = getNodeTopology(node)
+	}
+	return topology
+}
+
+func setLocalTopology(topology nodeTopology) {
+	localTopologyMux.Lock()
+	defer localTopologyMux.Unlock()
+	localTopology = topology
+}
+
+func getLocalTopology() nodeTopology {
+	localTopologyMux.RLock()
+	defer localTopologyMux.RUnlock()
+	return localTopology
+}
+
+// zoneOrUnknown returns the given zone or region, or unknownZone if it is empty
+func zoneOrUnknown(zone string) string {
+	if zone == "" {
+		return unknownZone
+	}
+	return zone
+}
@@ -15,6 +15,7 @@
 package goldpinger
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -36,7 +37,7 @@ var checkResultsMux = sync.Mutex{}
 // - the pinger has the same hostIP
 func exists(existingPods map[string]*GoldpingerPod, podName string, new *GoldpingerPod) bool {
 	old, exists := existingPods[podName]
-	return exists && (old.PodIP == new.PodIP) && (old.HostIP == new.HostIP)
+	return exists && (old.PodIP == new.PodIP) && (old.HostIP == new.HostIP) && (old.Maintenance == new.Maintenance)
 }
 
 // updatePingers calls SelectPods() at regular intervals to get a new list of goldpinger pods to ping
@@ -80,14 +81,22 @@ func updatePingers(resultsChan chan<- PingAllPodsResult) {
 
 		// Next create pingers for new pods
 		createPingers(pingers, newPods, resultsChan, refreshPeriod)
+		recordPingers(pingers)
 
 		// Finally, just set existingPods to the latest and collect garbage
 		existingPods = latest
 		deletedPods = nil
 		newPods = nil
 
-		// Wait the given time before pinging
-		time.Sleep(refreshPeriod)
+		// Wait the given time before pinging, unless we're shutting down
+		select {
+		case <-stopUpdater:
+			destroyPingers(pingers, existingPods)
+			pingersWG.Wait()
+			close(updaterStopped)
+			return
+		case <-time.After(refreshPeriod):
+		}
 	}
 }
 
@@ -116,7 +125,13 @@ func createPingers(pingers map[string]*Pinger, newPods map[string]*GoldpingerPod
 	for podName, pod := range newPods {
 		pinger := NewPinger(pod, resultsChan)
 		pingers[podName] = pinger
-		go pinger.PingContinuously(initialWait, refreshPeriod, GoldpingerConfig.JitterFactor)
+		pingersWG.Add(1)
+		go func(initialWait time.Duration) {
+			defer pingersWG.Done()
+			incPingerGoroutines(1)
+			defer incPingerGoroutines(-1)
+			pinger.PingContinuously(initialWait, refreshPeriod, GoldpingerConfig.JitterFactor)
+		}(initialWait)
 		initialWait += waitBetweenPods
 	}
 }
@@ -146,18 +161,38 @@ func updateCounters() {
 	checkResultsMux.Lock()
 	defer checkResultsMux.Unlock()
 
-	var counterHealthy float64
+	now := time.Now()
+	var counterHealthy, counterUnknown, counterMaintenance, counterDeparted float64
+	states := make(map[string]float64, len(peerStates))
+	for _, state := range peerStates {
+		states[state] = 0
+	}
 	for _, result := range checkResults.PodResults {
-		if result.OK != nil && *result.OK {
+		result = stampPodResult(result, now)
+		if result.State != "" {
+			states[result.State]++
+		}
+		// a peer is only unhealthy once it's down, not on every dropped ping
+		if result.Status != models.PodResultStatusUnknown && isPeerUp(result) {
 			counterHealthy++
+		} else if result.Departed {
+			// peers that announced their departure are expected to fail
+			counterDeparted++
+		} else if result.Maintenance != "" && GoldpingerConfig.UnavailablePeerPolicy == UnavailablePeerPolicyMaintenance {
+			// unavailable peers failing their pings don't make the cluster unhealthy
+			counterMaintenance++
+		} else if result.Status == models.PodResultStatusUnknown {
+			counterUnknown++
 		}
 	}
-	CountHealthyUnhealthyNodes(counterHealthy, float64(len(checkResults.PodResults))-counterHealthy)
+	counterUnhealthy := float64(len(checkResults.PodResults)) - counterHealthy - counterUnknown - counterMaintenance - counterDeparted
+	CountHealthyUnhealthyNodes(counterHealthy, counterUnhealthy, counterUnknown, counterMaintenance, counterDeparted)
+	SetPeerStates(states)
 	// check external targets, don't block the access to checkResultsMux
-	nodesHealthy := int(counterHealthy) == len(checkResults.PodResults)
+	nodesHealthy := counterUnhealthy == 0 && counterUnknown == 0
 	go func(healthySoFar bool) {
 		if healthySoFar {
-			probeResults := checkTargets()
+			probeResults := checkTargets(context.Background())
 			for host := range probeResults {
 				for _, response := range probeResults[host] {
 					if response.Error != "" {
@@ -175,11 +210,16 @@ func updateCounters() {
 func collectResults(resultsChan <-chan PingAllPodsResult) {
 	refreshPeriod := time.Duration(GoldpingerConfig.RefreshInterval) * time.Second
 	updateTicker := time.NewTicker(refreshPeriod)
+	recordCollectorTick()
 	for {
 		select {
+		case <-stopCollector:
+			updateTicker.Stop()
+			return
 		case <-updateTicker.C:
 			// Every time our update ticker ticks, update the count of healthy/unhealthy nodes
 			updateCounters()
+			recordCollectorTick()
 		case response := <-resultsChan:
 			// On getting a ping response, if the pinger is not being deleted,
 			// simply save it for later
@@ -190,6 +230,7 @@ func collectResults(resultsChan <-chan PingAllPodsResult) {
 				checkResults.PodResults[response.podName] = response.podResult
 			}
 			checkResultsMux.Unlock()
+			recordResult(response)
 		}
 	}
 }
@@ -200,10 +241,15 @@ func StartUpdater() {
 		return
 	}
 
+	startProbeScheduler()
+
 	pods := SelectPods()
 
 	// Create a channel for the results
 	resultsChan := make(chan PingAllPodsResult, len(pods))
+	setResultsChan(resultsChan)
 	go updatePingers(resultsChan)
 	go collectResults(resultsChan)
+	go watchPipeline()
+	updaterRunning.Store(true)
 }
@@ -0,0 +1,135 @@
+// Copyright 2018 Bloomberg Finance L.P.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldpinger
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bloomberg/goldpinger/v3/pkg/models"
+	"github.com/go-openapi/strfmt"
+)
+
+// zoneLink identifies the pings made from a source zone (or region) to a destination one
+type zoneLink struct {
+	source      string
+	destination string
+}
+
+// zoneLinkStats accumulates the outcome of the pings made over a zoneLink
+type zoneLinkStats struct {
+	calls          int64
+	failures       int64
+	responseTimeMs int64
+	maxResponseMs  int64
+}
+
+// add records the outcome of a single ping
+func (s *zoneLinkStats) add(result models.PodResult) {
+	s.calls++
+	if result.OK == nil || !*result.OK {
+		s.failures++
+		return
+	}
+	s.responseTimeMs += result.ResponseTimeMs
+	if result.ResponseTimeMs > s.maxResponseMs {
+		s.maxResponseMs = result.ResponseTimeMs
+	}
+}
+
+// CheckZoneMatrix does a CheckAllPods and aggregates the results by source and destination zone and region
+func CheckZoneMatrix(ctx context.Context) *models.ZoneMatrixResults {
+	start := time.Now()
+	output := models.ZoneMatrixResults{
+		GeneratedAt: strfmt.DateTime(start),
+	}
+	selectedPods := SelectPods()
+
+	// the responses of check_all are keyed by the display name of the pods
+	sources := make(map[string]*GoldpingerPod)
+	for _, pod := range selectedPods {
+		sources[pod.Name] = pod
+	}
+
+	checkAll := CheckAllPods(ctx, selectedPods, CheckQuery{})
+
+	zoneLinks := make(map[zoneLink]*zoneLinkStats)
+	regionLinks := make(map[zoneLink]*zoneLinkStats)
+	for podName, resp := range checkAll.Responses {
+		if resp.Response == nil {
+			continue
+		}
+		sourceZone, sourceRegion := unknownZone, unknownZone
+		if source, ok := sources[podName]; ok {
+			sourceZone = zoneOrUnknown(source.Zone)
+			sourceRegion = zoneOrUnknown(source.Region)
+		}
+		for _, peer := range resp.Response.PodResults {
+			addZoneLinkResult(zoneLinks, zoneLink{sourceZone, zoneOrUnknown(peer.Zone)}, peer)
+			addZoneLinkResult(regionLinks, zoneLink{sourceRegion, zoneOrUnknown(peer.Region)}, peer)
+		}
+	}
+
+	output.Zones, output.Links = getZoneLinks(zoneLinks)
+	output.Regions, output.RegionLinks = getZoneLinks(regionLinks)
+	output.DurationNs = time.Since(start).Nanoseconds()
+	return &output
+}
+
+func addZoneLinkResult(links map[zoneLink]*zoneLinkStats, link zoneLink, result models.PodResult) {
+	stats, ok := links[link]
+	if !ok {
+		stats = &zoneLinkStats{}
+		links[link] = stats
+	}
+	stats.add(result)
+}
+
+// getZoneLinks returns the sorted list of zones seen and the stats of each link between them
+func getZoneLinks(links map[zoneLink]*zoneLinkStats) ([]string, []*models.ZoneLink) {
+	seen := make(map[string]bool)
+	results := make([]*models.ZoneLink, 0, len(links))
+	for link, stats := range links {
+		seen[link.source] = true
+		seen[link.destination] = true
+
+		result := &models.ZoneLink{
+			Source:            link.source,
+			Destination:       link.destination,
+			Calls:             stats.calls,
+			Failures:          stats.failures,
+			Loss:              float64(stats.failures) / float64(stats.calls),
+			MaxResponseTimeMs: stats.maxResponseMs,
+		}
+		if successes := stats.calls - stats.failures; successes > 0 {
+			result.AvgResponseTimeMs = float64(stats.responseTimeMs) / float64(successes)
+		}
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Source != results[j].Source {
+			return results[i].Source < results[j].Source
+		}
+		return results[i].Destination < results[j].Destination
+	})
+
+	zones := make([]string, 0, len(seen))
+	for zone := range seen {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones, results
+}
@@ -7,6 +7,7 @@ package models
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/strfmt"
@@ -36,6 +37,10 @@ type CheckAllPodResult struct {
 	// response
 	Response *CheckResults `json:"response,omitempty"`
 
+	// ok, failed when the peer couldn't be checked, or unknown when some of its results are stale
+	// Enum: [ok failed unknown]
+	Status string `json:"status,omitempty"`
+
 	// status code
 	StatusCode int32 `json:"status-code,omitempty"`
 }
@@ -56,6 +61,10 @@ func (m *CheckAllPodResult) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateStatus(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -105,6 +114,51 @@ func (m *CheckAllPodResult) validateResponse(formats strfmt.Registry) error {
 	return nil
 }
 
+var checkAllPodResultTypeStatusPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["ok","failed","unknown"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		checkAllPodResultTypeStatusPropEnum = append(checkAllPodResultTypeStatusPropEnum, v)
+	}
+}
+
+const (
+
+	// CheckAllPodResultStatusOk captures enum value "ok"
+	CheckAllPodResultStatusOk string = "ok"
+
+	// CheckAllPodResultStatusFailed captures enum value "failed"
+	CheckAllPodResultStatusFailed string = "failed"
+
+	// CheckAllPodResultStatusUnknown captures enum value "unknown"
+	CheckAllPodResultStatusUnknown string = "unknown"
+)
+
+// prop value enum
+func (m *CheckAllPodResult) validateStatusEnum(path, location string, value string) error {
+	if err := validate.EnumCase(path, location, value, checkAllPodResultTypeStatusPropEnum, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *CheckAllPodResult) validateStatus(formats strfmt.Registry) error {
+	if swag.IsZero(m.Status) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateStatusEnum("status", "body", m.Status); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // ContextValidate validate this check all pod result based on the context it is used
 func (m *CheckAllPodResult) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
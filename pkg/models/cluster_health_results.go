@@ -23,6 +23,9 @@ type ClusterHealthResults struct {
 	// Required: true
 	OK bool `json:"OK"`
 
+	// how many other instances ping each node (its in-degree), keyed by host IP
+	Coverage map[string]int64 `json:"coverage,omitempty"`
+
 	// duration ns
 	DurationNs int64 `json:"duration-ns,omitempty"`
 
@@ -30,12 +33,24 @@ type ClusterHealthResults struct {
 	// Format: date-time
 	GeneratedAt strfmt.DateTime `json:"generated-at,omitempty"`
 
+	// nodes whose clock is off by more than the configured maximum, as seen by most of their peers
+	NodesClockSkewed []string `json:"nodesClockSkewed"`
+
+	// nodes whose instance announced that it is shutting down, reported instead of expected
+	NodesDeparted []string `json:"nodesDeparted"`
+
 	// nodes healthy
 	NodesHealthy []string `json:"nodesHealthy"`
 
+	// unavailable nodes, reported as in maintenance instead of expected, with the maintenance unavailable peer policy
+	NodesMaintenance []string `json:"nodesMaintenance"`
+
 	// nodes total
 	NodesTotal int64 `json:"nodesTotal,omitempty"`
 
+	// nodes pinged by fewer than the configured minimum number of peers
+	NodesUnderObserved []string `json:"nodesUnderObserved"`
+
 	// nodes unhealthy
 	NodesUnhealthy []string `json:"nodesUnhealthy"`
 }
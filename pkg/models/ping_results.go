@@ -23,8 +23,18 @@ type PingResults struct {
 	// Format: date-time
 	BootTime strfmt.DateTime `json:"boot_time,omitempty"`
 
+	// the name of the node the responder runs on
+	NodeName string `json:"node_name,omitempty"`
+
 	// received
 	Received *CallStats `json:"received,omitempty"`
+
+	// the wall clock time of the responder when it handled the request
+	// Format: date-time
+	ServerTime strfmt.DateTime `json:"server_time,omitempty"`
+
+	// the remote address the responder saw the request come from
+	SourceIP string `json:"source_ip,omitempty"`
 }
 
 // Validate validates this ping results
@@ -39,6 +49,10 @@ func (m *PingResults) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateServerTime(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -76,6 +90,18 @@ func (m *PingResults) validateReceived(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *PingResults) validateServerTime(formats strfmt.Registry) error {
+	if swag.IsZero(m.ServerTime) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("server_time", "body", "date-time", m.ServerTime.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // ContextValidate validate this ping results based on the context it is used
 func (m *PingResults) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
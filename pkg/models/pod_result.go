@@ -7,6 +7,7 @@ package models
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/strfmt"
@@ -34,17 +35,75 @@ type PodResult struct {
 	// Format: ipv4
 	PodIP strfmt.IPv4 `json:"PodIP,omitempty"`
 
+	// how long ago the ping was made, in milliseconds
+	AgeMs int64 `json:"age-ms,omitempty"`
+
+	// number of attempts, including the retries with --ping-retries
+	Attempts int64 `json:"attempts,omitempty"`
+
+	// estimated offset of the peer's clock, in milliseconds
+	ClockOffsetMs int64 `json:"clock-offset-ms,omitempty"`
+
+	// number of pings failed in a row
+	ConsecutiveFailures int64 `json:"consecutive-failures,omitempty"`
+
+	// number of pings succeeded in a row
+	ConsecutiveSuccesses int64 `json:"consecutive-successes,omitempty"`
+
+	// true when the peer announced that it is shutting down
+	Departed bool `json:"departed,omitempty"`
+
 	// error
 	Error string `json:"error,omitempty"`
 
+	// why the call failed
+	// Enum: [dns connection_refused connect_timeout read_timeout reset tls http_status other]
+	ErrorReason string `json:"error-reason,omitempty"`
+
+	// why the peer or its node is unavailable (terminating, not ready, cordoned or tainted), if it is
+	Maintenance string `json:"maintenance,omitempty"`
+
+	// the extra labels of the peer's node, selected with --node-labels
+	NodeLabels map[string]string `json:"node-labels,omitempty"`
+
+	// per network path results, when pinging over several paths
+	PathResults map[string]PathResult `json:"pathResults,omitempty"`
+
+	// the region of the peer's node
+	Region string `json:"region,omitempty"`
+
 	// response
 	Response *PingResults `json:"response,omitempty"`
 
 	// wall clock time in milliseconds
 	ResponseTimeMs int64 `json:"response-time-ms,omitempty"`
 
+	// the role of the discovery source of the peer, peer or canary
+	Role string `json:"role,omitempty"`
+
+	// the name of the discovery source of the peer
+	Source string `json:"source,omitempty"`
+
+	// up, degraded while failing fewer than --peer-down-after pings in a row, down until it succeeds
+	// --peer-up-after pings in a row, or flapping
+	// Enum: [up degraded down flapping]
+	State string `json:"state,omitempty"`
+
+	// ok or failed, or unknown when the result is older than the stale result threshold
+	// Enum: [ok failed unknown]
+	Status string `json:"status,omitempty"`
+
 	// status code
 	StatusCode int32 `json:"status-code,omitempty"`
+
+	// number of transitions between up and down within --peer-flap-window
+	Transitions int64 `json:"transitions,omitempty"`
+
+	// true when the peer saw the ping come from another IP than this pod's IP
+	UnexpectedSnat bool `json:"unexpected-snat,omitempty"`
+
+	// the zone of the peer's node
+	Zone string `json:"zone,omitempty"`
 }
 
 // Validate validates this pod result
@@ -63,10 +122,26 @@ func (m *PodResult) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateErrorReason(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validatePathResults(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateResponse(formats); err != nil {
 		res = append(res, err)
 	}
 
+	if err := m.validateState(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateStatus(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -109,6 +184,92 @@ func (m *PodResult) validatePodIP(formats strfmt.Registry) error {
 	return nil
 }
 
+var podResultTypeErrorReasonPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["dns","connection_refused","connect_timeout","read_timeout","reset","tls","http_status","other"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		podResultTypeErrorReasonPropEnum = append(podResultTypeErrorReasonPropEnum, v)
+	}
+}
+
+const (
+
+	// PodResultErrorReasonDNS captures enum value "dns"
+	PodResultErrorReasonDNS string = "dns"
+
+	// PodResultErrorReasonConnectionRefused captures enum value "connection_refused"
+	PodResultErrorReasonConnectionRefused string = "connection_refused"
+
+	// PodResultErrorReasonConnectTimeout captures enum value "connect_timeout"
+	PodResultErrorReasonConnectTimeout string = "connect_timeout"
+
+	// PodResultErrorReasonReadTimeout captures enum value "read_timeout"
+	PodResultErrorReasonReadTimeout string = "read_timeout"
+
+	// PodResultErrorReasonReset captures enum value "reset"
+	PodResultErrorReasonReset string = "reset"
+
+	// PodResultErrorReasonTLS captures enum value "tls"
+	PodResultErrorReasonTLS string = "tls"
+
+	// PodResultErrorReasonHTTPStatus captures enum value "http_status"
+	PodResultErrorReasonHTTPStatus string = "http_status"
+
+	// PodResultErrorReasonOther captures enum value "other"
+	PodResultErrorReasonOther string = "other"
+)
+
+// prop value enum
+func (m *PodResult) validateErrorReasonEnum(path, location string, value string) error {
+	if err := validate.EnumCase(path, location, value, podResultTypeErrorReasonPropEnum, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *PodResult) validateErrorReason(formats strfmt.Registry) error {
+	if swag.IsZero(m.ErrorReason) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateErrorReasonEnum("error-reason", "body", m.ErrorReason); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *PodResult) validatePathResults(formats strfmt.Registry) error {
+	if swag.IsZero(m.PathResults) { // not required
+		return nil
+	}
+
+	for k := range m.PathResults {
+
+		if err := validate.Required("pathResults"+"."+k, "body", m.PathResults[k]); err != nil {
+			return err
+		}
+		if val, ok := m.PathResults[k]; ok {
+			if err := val.Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("pathResults" + "." + k)
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("pathResults" + "." + k)
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
 func (m *PodResult) validateResponse(formats strfmt.Registry) error {
 	if swag.IsZero(m.Response) { // not required
 		return nil
@@ -128,10 +289,107 @@ func (m *PodResult) validateResponse(formats strfmt.Registry) error {
 	return nil
 }
 
+var podResultTypeStatePropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["up","degraded","down","flapping"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		podResultTypeStatePropEnum = append(podResultTypeStatePropEnum, v)
+	}
+}
+
+const (
+
+	// PodResultStateUp captures enum value "up"
+	PodResultStateUp string = "up"
+
+	// PodResultStateDegraded captures enum value "degraded"
+	PodResultStateDegraded string = "degraded"
+
+	// PodResultStateDown captures enum value "down"
+	PodResultStateDown string = "down"
+
+	// PodResultStateFlapping captures enum value "flapping"
+	PodResultStateFlapping string = "flapping"
+)
+
+// prop value enum
+func (m *PodResult) validateStateEnum(path, location string, value string) error {
+	if err := validate.EnumCase(path, location, value, podResultTypeStatePropEnum, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *PodResult) validateState(formats strfmt.Registry) error {
+	if swag.IsZero(m.State) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateStateEnum("state", "body", m.State); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var podResultTypeStatusPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["ok","failed","unknown"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		podResultTypeStatusPropEnum = append(podResultTypeStatusPropEnum, v)
+	}
+}
+
+const (
+
+	// PodResultStatusOk captures enum value "ok"
+	PodResultStatusOk string = "ok"
+
+	// PodResultStatusFailed captures enum value "failed"
+	PodResultStatusFailed string = "failed"
+
+	// PodResultStatusUnknown captures enum value "unknown"
+	PodResultStatusUnknown string = "unknown"
+)
+
+// prop value enum
+func (m *PodResult) validateStatusEnum(path, location string, value string) error {
+	if err := validate.EnumCase(path, location, value, podResultTypeStatusPropEnum, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *PodResult) validateStatus(formats strfmt.Registry) error {
+	if swag.IsZero(m.Status) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateStatusEnum("status", "body", m.Status); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // ContextValidate validate this pod result based on the context it is used
 func (m *PodResult) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.contextValidatePathResults(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.contextValidateResponse(ctx, formats); err != nil {
 		res = append(res, err)
 	}
@@ -142,6 +400,21 @@ func (m *PodResult) ContextValidate(ctx context.Context, formats strfmt.Registry
 	return nil
 }
 
+func (m *PodResult) contextValidatePathResults(ctx context.Context, formats strfmt.Registry) error {
+
+	for k := range m.PathResults {
+
+		if val, ok := m.PathResults[k]; ok {
+			if err := val.ContextValidate(ctx, formats); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
 func (m *PodResult) contextValidateResponse(ctx context.Context, formats strfmt.Registry) error {
 
 	if m.Response != nil {
@@ -7,9 +7,12 @@ package models
 
 import (
 	"context"
+	"encoding/json"
 
+	"github.com/go-openapi/errors"
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
 )
 
 // ProbeResult probe result
@@ -20,15 +23,96 @@ type ProbeResult struct {
 	// error
 	Error string `json:"error,omitempty"`
 
+	// expect
+	// Enum: [allow deny]
+	Expect string `json:"expect,omitempty"`
+
+	// when the probe was made
+	// Format: date-time
+	ProbeTime strfmt.DateTime `json:"probe-time,omitempty"`
+
+	// true when the target wasn't probed yet
+	Pending bool `json:"pending,omitempty"`
+
 	// protocol
 	Protocol string `json:"protocol,omitempty"`
 
 	// response time ms
 	ResponseTimeMs int64 `json:"response-time-ms,omitempty"`
+
+	// true when a target expected to be denied was reachable
+	Violation bool `json:"violation,omitempty"`
 }
 
 // Validate validates this probe result
 func (m *ProbeResult) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateExpect(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateProbeTime(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+var probeResultTypeExpectPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["allow","deny"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		probeResultTypeExpectPropEnum = append(probeResultTypeExpectPropEnum, v)
+	}
+}
+
+const (
+
+	// ProbeResultExpectAllow captures enum value "allow"
+	ProbeResultExpectAllow string = "allow"
+
+	// ProbeResultExpectDeny captures enum value "deny"
+	ProbeResultExpectDeny string = "deny"
+)
+
+// prop value enum
+func (m *ProbeResult) validateExpectEnum(path, location string, value string) error {
+	if err := validate.EnumCase(path, location, value, probeResultTypeExpectPropEnum, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *ProbeResult) validateExpect(formats strfmt.Registry) error {
+	if swag.IsZero(m.Expect) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateExpectEnum("expect", "body", m.Expect); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *ProbeResult) validateProbeTime(formats strfmt.Registry) error {
+	if swag.IsZero(m.ProbeTime) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("probe-time", "body", "date-time", m.ProbeTime.String(), formats); err != nil {
+		return err
+	}
+
 	return nil
 }
 
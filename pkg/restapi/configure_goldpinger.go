@@ -26,6 +26,7 @@ import (
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
 	"go.uber.org/zap"
 
 	"github.com/bloomberg/goldpinger/v3/pkg/goldpinger"
@@ -64,46 +65,49 @@ func configureAPI(api *operations.GoldpingerAPI) http.Handler {
 			)
 			defer cancel()
 
-			return operations.NewPingOK().WithPayload(goldpinger.GetStats(ctx))
+			return operations.NewPingOK().WithPayload(goldpinger.GetStats(ctx, params.HTTPRequest.RemoteAddr))
 		})
 
 	api.CheckServicePodsHandler = operations.CheckServicePodsHandlerFunc(
 		func(params operations.CheckServicePodsParams) middleware.Responder {
 			goldpinger.CountCall("received", "check")
 
+			query := goldpinger.NewCheckQuery(params.TimeoutSeconds, params.Include, params.Exclude, params.Filter)
 			ctx, cancel := context.WithTimeout(
 				params.HTTPRequest.Context(),
-				goldpinger.GoldpingerConfig.CheckTimeout,
+				query.GetTimeout(goldpinger.GoldpingerConfig.CheckTimeout, goldpinger.GoldpingerConfig.MaxCheckTimeout),
 			)
 			defer cancel()
 
-			return operations.NewCheckServicePodsOK().WithPayload(goldpinger.CheckNeighbours(ctx))
+			return operations.NewCheckServicePodsOK().WithPayload(goldpinger.CheckNeighbours(ctx, query))
 		})
 
 	api.CheckAllPodsHandler = operations.CheckAllPodsHandlerFunc(
 		func(params operations.CheckAllPodsParams) middleware.Responder {
 			goldpinger.CountCall("received", "check_all")
 
+			query := goldpinger.NewCheckQuery(params.TimeoutSeconds, params.Include, params.Exclude, params.Filter)
 			ctx, cancel := context.WithTimeout(
 				params.HTTPRequest.Context(),
-				goldpinger.GoldpingerConfig.CheckAllTimeout,
+				query.GetTimeout(goldpinger.GoldpingerConfig.CheckAllTimeout, goldpinger.GoldpingerConfig.MaxCheckAllTimeout),
 			)
 			defer cancel()
 
-			return operations.NewCheckAllPodsOK().WithPayload(goldpinger.CheckNeighboursNeighbours(ctx))
+			return operations.NewCheckAllPodsOK().WithPayload(goldpinger.CheckNeighboursNeighbours(ctx, query))
 		})
 
 	api.ClusterHealthHandler = operations.ClusterHealthHandlerFunc(
 		func(params operations.ClusterHealthParams) middleware.Responder {
 			goldpinger.CountCall("received", "cluster_health")
 
+			query := goldpinger.NewCheckQuery(params.TimeoutSeconds, params.Include, params.Exclude, params.Filter)
 			ctx, cancel := context.WithTimeout(
 				params.HTTPRequest.Context(),
-				goldpinger.GoldpingerConfig.CheckAllTimeout,
+				query.GetTimeout(goldpinger.GoldpingerConfig.CheckAllTimeout, goldpinger.GoldpingerConfig.MaxCheckAllTimeout),
 			)
 			defer cancel()
 
-			payload := goldpinger.CheckCluster(ctx)
+			payload := goldpinger.CheckCluster(ctx, query)
 			if payload.OK {
 				return operations.NewClusterHealthOK().WithPayload(payload)
 			} else {
@@ -111,6 +115,32 @@ func configureAPI(api *operations.GoldpingerAPI) http.Handler {
 			}
 		})
 
+	api.ZoneMatrixHandler = operations.ZoneMatrixHandlerFunc(
+		func(params operations.ZoneMatrixParams) middleware.Responder {
+			goldpinger.CountCall("received", "zone_matrix")
+
+			ctx, cancel := context.WithTimeout(
+				params.HTTPRequest.Context(),
+				goldpinger.GoldpingerConfig.CheckAllTimeout,
+			)
+			defer cancel()
+
+			return operations.NewZoneMatrixOK().WithPayload(goldpinger.CheckZoneMatrix(ctx))
+		})
+
+	api.FederationHandler = operations.FederationHandlerFunc(
+		func(params operations.FederationParams) middleware.Responder {
+			goldpinger.CountCall("received", "federation")
+
+			ctx, cancel := context.WithTimeout(
+				params.HTTPRequest.Context(),
+				goldpinger.GoldpingerConfig.CheckAllTimeout,
+			)
+			defer cancel()
+
+			return operations.NewFederationOK().WithPayload(goldpinger.CheckFederation(ctx))
+		})
+
 	api.HealthzHandler = operations.HealthzHandlerFunc(
 		func(params operations.HealthzParams) middleware.Responder {
 			goldpinger.CountCall("received", "healthz")
@@ -122,7 +152,37 @@ func configureAPI(api *operations.GoldpingerAPI) http.Handler {
 			}
 		})
 
-	api.ServerShutdown = func() {}
+	api.ReadyzHandler = operations.ReadyzHandlerFunc(
+		func(params operations.ReadyzParams) middleware.Responder {
+			goldpinger.CountCall("received", "readyz")
+			readinessResult := goldpinger.ReadinessCheck()
+			if *readinessResult.OK {
+				return operations.NewReadyzOK().WithPayload(readinessResult)
+			}
+			return operations.NewReadyzServiceUnavailable().WithPayload(readinessResult)
+		})
+
+	api.DebugHandler = operations.DebugHandlerFunc(
+		func(params operations.DebugParams) middleware.Responder {
+			goldpinger.CountCall("received", "debug")
+			return operations.NewDebugOK().WithPayload(goldpinger.GetDiagnostics())
+		})
+
+	api.DepartHandler = operations.DepartHandlerFunc(
+		func(params operations.DepartParams) middleware.Responder {
+			goldpinger.CountCall("received", "depart")
+			results := goldpinger.RecordDeparture(params.Notice, params.HTTPRequest.RemoteAddr)
+			if !*results.OK {
+				return operations.NewDepartForbidden().WithPayload(results)
+			}
+			return operations.NewDepartOK().WithPayload(results)
+		})
+
+	api.PreServerShutdown = goldpinger.Shutdown
+
+	api.ServerShutdown = func() {
+		zap.L().Info("Drained the API, exiting")
+	}
 
 	return setupGlobalMiddleware(api.Serve(setupMiddlewares))
 }
@@ -130,6 +190,17 @@ func configureAPI(api *operations.GoldpingerAPI) http.Handler {
 // The TLS configuration before HTTPS server starts.
 func configureTLS(tlsConfig *tls.Config) {
 	// Make all necessary changes to the TLS configuration here.
+	goldpinger.ConfigurePeerTLSServer(tlsConfig)
+
+	// with authentication, the client certificates are checked by the authentication middleware,
+	// so that the open paths stay reachable without one
+	if len(goldpinger.GoldpingerConfig.AuthModes) > 0 && tlsConfig.ClientAuth == tls.RequireAndVerifyClientCert {
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		zap.L().Info("Client certificates are only verified when given, the authentication middleware requires them outside of the open paths",
+			zap.Strings("authModes", goldpinger.GoldpingerConfig.AuthModes),
+			zap.Strings("openPaths", goldpinger.GoldpingerConfig.AuthOpenPaths),
+		)
+	}
 }
 
 // As soon as server is initialized but not run yet, this function will be called.
@@ -152,7 +223,9 @@ func fileServerMiddleware(next http.Handler) http.Handler {
 		if r.URL.Path == "/" {
 			http.StripPrefix("/", fileServer).ServeHTTP(w, r)
 		} else if r.URL.Path == "/heatmap.png" {
-			goldpinger.HeatmapHandler(w, r)
+			serveHeatmap(w, r)
+		} else if r.URL.Path == "/federation/heatmap.png" {
+			goldpinger.FederationHeatmapHandler(w, r)
 		} else if strings.HasPrefix(r.URL.Path, "/static/") {
 			http.StripPrefix("/static/", fileServer).ServeHTTP(w, r)
 		} else {
@@ -162,6 +235,19 @@ func fileServerMiddleware(next http.Handler) http.Handler {
 
 }
 
+// serveHeatmap serves /heatmap.png, outside of the swagger API. Its query parameters are the ones of
+// /check_all, and are bound and validated as they are for /check_all
+func serveHeatmap(w http.ResponseWriter, r *http.Request) {
+	params := operations.NewCheckAllPodsParams()
+	route := &middleware.MatchedRoute{}
+	route.Formats = strfmt.Default
+	if err := params.BindRequest(r, route); err != nil {
+		errors.ServeError(w, r, err)
+		return
+	}
+	goldpinger.HeatmapHandler(w, r, goldpinger.NewCheckQuery(params.TimeoutSeconds, params.Include, params.Exclude, params.Filter))
+}
+
 func prometheusMetricsMiddleware(next http.Handler) http.Handler {
 	zap.L().Info("Added the prometheus middleware")
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -176,5 +262,6 @@ func prometheusMetricsMiddleware(next http.Handler) http.Handler {
 // The middleware configuration happens before anything, this middleware also applies to serving the swagger.json document.
 // So this is a good place to plug in a panic handling middleware, logging and metrics
 func setupGlobalMiddleware(handler http.Handler) http.Handler {
-	return prometheusMetricsMiddleware(fileServerMiddleware(handler))
+	// the calls are rate limited once authenticated, so that anonymous callers can't use up the limits
+	return goldpinger.AuthMiddleware(goldpinger.RateLimitMiddleware(goldpinger.ShutdownMiddleware(prometheusMetricsMiddleware(fileServerMiddleware(handler)))))
 }
@@ -31,6 +31,20 @@ func init() {
           "application/json"
         ],
         "operationId": "checkServicePods",
+        "parameters": [
+          {
+            "$ref": "#/parameters/timeout"
+          },
+          {
+            "$ref": "#/parameters/include"
+          },
+          {
+            "$ref": "#/parameters/exclude"
+          },
+          {
+            "$ref": "#/parameters/filter"
+          }
+        ],
         "responses": {
           "200": {
             "description": "Success, return response",
@@ -48,6 +62,20 @@ func init() {
           "application/json"
         ],
         "operationId": "checkAllPods",
+        "parameters": [
+          {
+            "$ref": "#/parameters/timeout"
+          },
+          {
+            "$ref": "#/parameters/include"
+          },
+          {
+            "$ref": "#/parameters/exclude"
+          },
+          {
+            "$ref": "#/parameters/filter"
+          }
+        ],
         "responses": {
           "200": {
             "description": "Success, return response",
@@ -65,6 +93,20 @@ func init() {
           "application/json"
         ],
         "operationId": "clusterHealth",
+        "parameters": [
+          {
+            "$ref": "#/parameters/timeout"
+          },
+          {
+            "$ref": "#/parameters/include"
+          },
+          {
+            "$ref": "#/parameters/exclude"
+          },
+          {
+            "$ref": "#/parameters/filter"
+          }
+        ],
         "responses": {
           "200": {
             "description": "Healthy cluster",
@@ -81,6 +123,77 @@ func init() {
         }
       }
     },
+    "/debug/goldpinger": {
+      "get": {
+        "description": "Report the internal state of the pinger pipeline, for troubleshooting",
+        "produces": [
+          "application/json"
+        ],
+        "operationId": "debug",
+        "responses": {
+          "200": {
+            "description": "return success",
+            "schema": {
+              "$ref": "#/definitions/DiagnosticsResults"
+            }
+          }
+        }
+      }
+    },
+    "/depart": {
+      "post": {
+        "description": "Notifies this instance that a peer is shutting down, so that it reports it as departed rather than unreachable",
+        "consumes": [
+          "application/json"
+        ],
+        "produces": [
+          "application/json"
+        ],
+        "operationId": "depart",
+        "parameters": [
+          {
+            "description": "the instance that is shutting down",
+            "name": "notice",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/DepartureNotice"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success, return response",
+            "schema": {
+              "$ref": "#/definitions/DepartResults"
+            }
+          },
+          "403": {
+            "description": "The notice doesn't come from the departing instance",
+            "schema": {
+              "$ref": "#/definitions/DepartResults"
+            }
+          }
+        }
+      }
+    },
+    "/federation": {
+      "get": {
+        "description": "Calls /cluster_health and /check_all on all the clusters of the federation, and aggregates their health and the latency between them",
+        "produces": [
+          "application/json"
+        ],
+        "operationId": "federation",
+        "responses": {
+          "200": {
+            "description": "Success, return response",
+            "schema": {
+              "$ref": "#/definitions/FederationResults"
+            }
+          }
+        }
+      }
+    },
     "/healthz": {
       "get": {
         "description": "The healthcheck endpoint provides detailed information about the health of a web service. If each of the components required by the service are healthy, then the service is considered healthy and will return a 200 OK response. If any of the components needed by the service are unhealthy, then a 503 Service Unavailable response will be provided.",
@@ -120,6 +233,46 @@ func init() {
           }
         }
       }
+    },
+    "/readyz": {
+      "get": {
+        "description": "The readiness endpoint succeeds once this instance discovered its peers, started pinging them and collected a first round of results. It reports the status of each component, and returns a 503 Service Unavailable response while any of them isn't ready.",
+        "produces": [
+          "application/json"
+        ],
+        "operationId": "readyz",
+        "responses": {
+          "200": {
+            "description": "Readiness report",
+            "schema": {
+              "$ref": "#/definitions/ReadinessResults"
+            }
+          },
+          "503": {
+            "description": "Instance not ready",
+            "schema": {
+              "$ref": "#/definitions/ReadinessResults"
+            }
+          }
+        }
+      }
+    },
+    "/zone_matrix": {
+      "get": {
+        "description": "Calls /check on all the pods, and aggregates the results into the latency and loss between each pair of zones and regions",
+        "produces": [
+          "application/json"
+        ],
+        "operationId": "zoneMatrix",
+        "responses": {
+          "200": {
+            "description": "Success, return response",
+            "schema": {
+              "$ref": "#/definitions/ZoneMatrixResults"
+            }
+          }
+        }
+      }
     }
   },
   "definitions": {
@@ -157,6 +310,15 @@ func init() {
         "response": {
           "$ref": "#/definitions/CheckResults"
         },
+        "status": {
+          "description": "ok, failed when the peer couldn't be checked, or unknown when some of its results are stale",
+          "type": "string",
+          "enum": [
+            "ok",
+            "failed",
+            "unknown"
+          ]
+        },
         "status-code": {
           "type": "integer",
           "format": "int32"
@@ -170,12 +332,6 @@ func init() {
           "type": "boolean",
           "default": false
         },
-        "dnsResults": {
-          "type": "object",
-          "additionalProperties": {
-            "$ref": "#/definitions/DnsResults"
-          }
-        },
         "hosts": {
           "type": "array",
           "items": {
@@ -203,10 +359,10 @@ func init() {
           "type": "integer",
           "format": "int32"
         },
-        "httpResults": {
+        "probeResults": {
           "type": "object",
           "additionalProperties": {
-            "$ref": "#/definitions/HttpResults"
+            "$ref": "#/definitions/ProbeResults"
           }
         },
         "responses": {
@@ -214,32 +370,20 @@ func init() {
           "additionalProperties": {
             "$ref": "#/definitions/CheckAllPodResult"
           }
-        },
-        "tcpResults": {
-          "type": "object",
-          "additionalProperties": {
-            "$ref": "#/definitions/TcpResults"
-          }
         }
       }
     },
     "CheckResults": {
       "type": "object",
       "properties": {
-        "dnsResults": {
-          "$ref": "#/definitions/DnsResults"
-        },
-        "httpResults": {
-          "$ref": "#/definitions/HttpResults"
-        },
         "podResults": {
           "type": "object",
           "additionalProperties": {
             "$ref": "#/definitions/PodResult"
           }
         },
-        "tcpResults": {
-          "$ref": "#/definitions/TcpResults"
+        "probeResults": {
+          "$ref": "#/definitions/ProbeResults"
         }
       }
     },
@@ -253,6 +397,14 @@ func init() {
           "type": "boolean",
           "default": false
         },
+        "coverage": {
+          "description": "how many other instances ping each node (its in-degree), keyed by host IP",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "int64"
+          }
+        },
         "duration-ns": {
           "type": "integer",
           "format": "int64"
@@ -261,16 +413,44 @@ func init() {
           "type": "string",
           "format": "date-time"
         },
+        "nodesClockSkewed": {
+          "description": "nodes whose clock is off by more than the configured maximum, as seen by most of their peers",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "nodesDeparted": {
+          "description": "nodes whose instance announced that it is shutting down, reported instead of expected",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
         "nodesHealthy": {
           "type": "array",
           "items": {
             "type": "string"
           }
         },
+        "nodesMaintenance": {
+          "description": "unavailable nodes, reported as in maintenance instead of expected, with the maintenance unavailable peer policy",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
         "nodesTotal": {
           "type": "integer",
           "format": "int64"
         },
+        "nodesUnderObserved": {
+          "description": "nodes pinged by fewer than the configured minimum number of peers",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
         "nodesUnhealthy": {
           "type": "array",
           "items": {
@@ -279,71 +459,199 @@ func init() {
         }
       }
     },
-    "DnsResults": {
+    "ComponentStatus": {
       "type": "object",
-      "additionalProperties": {
-        "$ref": "#/definitions/ProbeResult"
+      "properties": {
+        "OK": {
+          "type": "boolean",
+          "default": false
+        },
+        "message": {
+          "description": "why the component is or isn't ready",
+          "type": "string"
+        }
       }
     },
-    "HealthCheckResults": {
+    "DepartResults": {
       "type": "object",
       "properties": {
         "OK": {
           "type": "boolean",
           "default": false
         },
-        "duration-ns": {
+        "expires-at": {
+          "description": "until when the instance is reported as departed",
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "DepartureNotice": {
+      "type": "object",
+      "required": [
+        "podIP"
+      ],
+      "properties": {
+        "hostIP": {
+          "type": "string"
+        },
+        "name": {
+          "description": "the name of the instance that is shutting down",
+          "type": "string"
+        },
+        "podIP": {
+          "type": "string"
+        }
+      }
+    },
+    "DiagnosticsResults": {
+      "type": "object",
+      "properties": {
+        "active-pingers": {
+          "description": "number of peers being pinged",
           "type": "integer",
           "format": "int64"
         },
         "generated-at": {
           "type": "string",
           "format": "date-time"
+        },
+        "goroutines": {
+          "description": "number of goroutines of the process",
+          "type": "integer",
+          "format": "int64"
+        },
+        "last-discovery": {
+          "description": "when discovery last succeeded",
+          "type": "string",
+          "format": "date-time"
+        },
+        "peers": {
+          "description": "the state of the pinger of each peer, by name",
+          "type": "object",
+          "additionalProperties": {
+            "$ref": "#/definitions/PeerDiagnostics"
+          }
+        },
+        "pinger-goroutines": {
+          "description": "number of pinger goroutines still running, including the ones being stopped",
+          "type": "integer",
+          "format": "int64"
+        },
+        "results-channel-capacity": {
+          "description": "capacity of the channel the pingers send their results to",
+          "type": "integer",
+          "format": "int64"
+        },
+        "results-channel-depth": {
+          "description": "number of results waiting for the collector",
+          "type": "integer",
+          "format": "int64"
+        },
+        "stale-peers": {
+          "description": "the peers with no result for longer than the stale threshold",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "stale-threshold-ms": {
+          "description": "how long a peer can go without a result before it's stale, in milliseconds",
+          "type": "integer",
+          "format": "int64"
         }
       }
     },
-    "HttpResults": {
+    "FederationClusterResult": {
       "type": "object",
-      "additionalProperties": {
-        "$ref": "#/definitions/ProbeResult"
+      "properties": {
+        "OK": {
+          "type": "boolean",
+          "default": false
+        },
+        "error": {
+          "type": "string"
+        },
+        "health": {
+          "$ref": "#/definitions/ClusterHealthResults"
+        }
       }
     },
-    "PingResults": {
+    "FederationResults": {
       "type": "object",
       "properties": {
-        "boot_time": {
+        "OK": {
+          "type": "boolean",
+          "default": false
+        },
+        "clusters": {
+          "type": "object",
+          "additionalProperties": {
+            "$ref": "#/definitions/FederationClusterResult"
+          }
+        },
+        "duration-ns": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "generated-at": {
           "type": "string",
           "format": "date-time"
         },
-        "received": {
-          "$ref": "#/definitions/CallStats"
+        "links": {
+          "description": "latency and loss between the gateways of each pair of clusters",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/ZoneLink"
+          }
         }
       }
     },
-    "PodResult": {
+    "HealthCheckResults": {
       "type": "object",
       "properties": {
-        "HostIP": {
-          "type": "string",
-          "format": "ipv4"
-        },
         "OK": {
           "type": "boolean",
           "default": false
         },
-        "PingTime": {
+        "duration-ns": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "generated-at": {
           "type": "string",
           "format": "date-time"
+        }
+      }
+    },
+    "PathResult": {
+      "type": "object",
+      "properties": {
+        "OK": {
+          "type": "boolean",
+          "default": false
         },
-        "PodIP": {
-          "type": "string",
-          "format": "ipv4"
+        "attempts": {
+          "description": "number of attempts, including the retries with --ping-retries",
+          "type": "number",
+          "format": "int64"
         },
         "error": {
           "type": "string"
         },
-        "response": {
-          "$ref": "#/definitions/PingResults"
+        "error-reason": {
+          "description": "why the call failed",
+          "type": "string",
+          "enum": [
+            "dns",
+            "connection_refused",
+            "connect_timeout",
+            "read_timeout",
+            "reset",
+            "tls",
+            "http_status",
+            "other"
+          ]
         },
         "response-time-ms": {
           "description": "wall clock time in milliseconds",
@@ -356,22 +664,375 @@ func init() {
         }
       }
     },
-    "ProbeResult": {
+    "PeerDiagnostics": {
+      "type": "object",
       "properties": {
-        "error": {
-          "type": "string"
+        "last-result": {
+          "description": "when the collector last received a result for the peer",
+          "type": "string",
+          "format": "date-time"
         },
-        "response-time-ms": {
-          "type": "number",
-          "format": "int64"
+        "pinger-started": {
+          "description": "when the pinger of the peer started",
+          "type": "string",
+          "format": "date-time"
+        },
+        "stale": {
+          "description": "true if no result was received for the peer for longer than the stale threshold",
+          "type": "boolean"
         }
       }
     },
-    "TcpResults": {
+    "PingResults": {
+      "type": "object",
+      "properties": {
+        "boot_time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "node_name": {
+          "description": "the name of the node the responder runs on",
+          "type": "string"
+        },
+        "received": {
+          "$ref": "#/definitions/CallStats"
+        },
+        "server_time": {
+          "description": "the wall clock time of the responder when it handled the request",
+          "type": "string",
+          "format": "date-time"
+        },
+        "source_ip": {
+          "description": "the remote address the responder saw the request come from",
+          "type": "string"
+        }
+      }
+    },
+    "PodResult": {
+      "type": "object",
+      "properties": {
+        "HostIP": {
+          "type": "string",
+          "format": "ipv4"
+        },
+        "OK": {
+          "type": "boolean",
+          "default": false
+        },
+        "PingTime": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "PodIP": {
+          "type": "string",
+          "format": "ipv4"
+        },
+        "age-ms": {
+          "description": "how long ago the ping was made, in milliseconds",
+          "type": "number",
+          "format": "int64"
+        },
+        "attempts": {
+          "description": "number of attempts, including the retries with --ping-retries",
+          "type": "number",
+          "format": "int64"
+        },
+        "clock-offset-ms": {
+          "description": "estimated offset of the peer's clock, in milliseconds",
+          "type": "number",
+          "format": "int64"
+        },
+        "consecutive-failures": {
+          "description": "number of pings failed in a row",
+          "type": "number",
+          "format": "int64"
+        },
+        "consecutive-successes": {
+          "description": "number of pings succeeded in a row",
+          "type": "number",
+          "format": "int64"
+        },
+        "departed": {
+          "description": "true when the peer announced that it is shutting down",
+          "type": "boolean"
+        },
+        "error": {
+          "type": "string"
+        },
+        "error-reason": {
+          "description": "why the call failed",
+          "type": "string",
+          "enum": [
+            "dns",
+            "connection_refused",
+            "connect_timeout",
+            "read_timeout",
+            "reset",
+            "tls",
+            "http_status",
+            "other"
+          ]
+        },
+        "maintenance": {
+          "description": "why the peer or its node is unavailable (terminating, not ready, cordoned or tainted), if it is",
+          "type": "string"
+        },
+        "node-labels": {
+          "description": "the extra labels of the peer's node, selected with --node-labels",
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        },
+        "pathResults": {
+          "description": "per network path results, when pinging over several paths",
+          "type": "object",
+          "additionalProperties": {
+            "$ref": "#/definitions/PathResult"
+          }
+        },
+        "region": {
+          "description": "the region of the peer's node",
+          "type": "string"
+        },
+        "response": {
+          "$ref": "#/definitions/PingResults"
+        },
+        "response-time-ms": {
+          "description": "wall clock time in milliseconds",
+          "type": "number",
+          "format": "int64"
+        },
+        "role": {
+          "description": "the role of the discovery source of the peer, peer or canary",
+          "type": "string"
+        },
+        "source": {
+          "description": "the name of the discovery source of the peer",
+          "type": "string"
+        },
+        "state": {
+          "description": "up, degraded while failing fewer than --peer-down-after pings in a row, down until it succeeds --peer-up-after pings in a row, or flapping",
+          "type": "string",
+          "enum": [
+            "up",
+            "degraded",
+            "down",
+            "flapping"
+          ]
+        },
+        "status": {
+          "description": "ok or failed, or unknown when the result is older than the stale result threshold",
+          "type": "string",
+          "enum": [
+            "ok",
+            "failed",
+            "unknown"
+          ]
+        },
+        "status-code": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "transitions": {
+          "description": "number of transitions between up and down within --peer-flap-window",
+          "type": "number",
+          "format": "int64"
+        },
+        "unexpected-snat": {
+          "description": "true when the peer saw the ping come from another IP than this pod's IP",
+          "type": "boolean"
+        },
+        "zone": {
+          "description": "the zone of the peer's node",
+          "type": "string"
+        }
+      }
+    },
+    "ProbeResult": {
+      "properties": {
+        "error": {
+          "type": "string"
+        },
+        "expect": {
+          "type": "string",
+          "enum": [
+            "allow",
+            "deny"
+          ]
+        },
+        "pending": {
+          "description": "true when the target wasn't probed yet",
+          "type": "boolean"
+        },
+        "probe-time": {
+          "description": "when the probe was made",
+          "type": "string",
+          "format": "date-time"
+        },
+        "protocol": {
+          "type": "string"
+        },
+        "response-time-ms": {
+          "type": "number",
+          "format": "int64"
+        },
+        "violation": {
+          "description": "true when a target expected to be denied was reachable",
+          "type": "boolean"
+        }
+      }
+    },
+    "ProbeResults": {
       "type": "object",
       "additionalProperties": {
-        "$ref": "#/definitions/ProbeResult"
+        "type": "array",
+        "items": {
+          "$ref": "#/definitions/ProbeResult"
+        }
+      }
+    },
+    "ReadinessResults": {
+      "type": "object",
+      "properties": {
+        "OK": {
+          "type": "boolean",
+          "default": false
+        },
+        "components": {
+          "description": "the status of each component, by name",
+          "type": "object",
+          "additionalProperties": {
+            "$ref": "#/definitions/ComponentStatus"
+          }
+        },
+        "duration-ns": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "generated-at": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "ZoneLink": {
+      "type": "object",
+      "properties": {
+        "avg-response-time-ms": {
+          "description": "average response time of the successful calls, in milliseconds",
+          "type": "number",
+          "format": "double"
+        },
+        "calls": {
+          "description": "number of pings made from the source to the destination",
+          "type": "integer",
+          "format": "int64"
+        },
+        "destination": {
+          "type": "string"
+        },
+        "failures": {
+          "description": "number of failed pings",
+          "type": "integer",
+          "format": "int64"
+        },
+        "loss": {
+          "description": "ratio of failed pings, between 0 and 1",
+          "type": "number",
+          "format": "double"
+        },
+        "max-response-time-ms": {
+          "description": "maximum response time of the successful calls, in milliseconds",
+          "type": "integer",
+          "format": "int64"
+        },
+        "source": {
+          "type": "string"
+        }
       }
+    },
+    "ZoneMatrixResults": {
+      "type": "object",
+      "properties": {
+        "duration-ns": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "generated-at": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "links": {
+          "description": "latency and loss between each pair of zones",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/ZoneLink"
+          }
+        },
+        "regionLinks": {
+          "description": "latency and loss between each pair of regions",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/ZoneLink"
+          }
+        },
+        "regions": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "zones": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    }
+  },
+  "parameters": {
+    "exclude": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "collectionFormat": "csv",
+      "description": "leave these peers out, by pod name, pod IP or host IP",
+      "name": "exclude",
+      "in": "query"
+    },
+    "filter": {
+      "type": "array",
+      "items": {
+        "pattern": "^(zone|region|source|role)=.+$",
+        "type": "string"
+      },
+      "collectionFormat": "csv",
+      "description": "only check the peers matching all these key=value filters, on their zone, region, source or role",
+      "name": "filter",
+      "in": "query"
+    },
+    "include": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "collectionFormat": "csv",
+      "description": "only check these peers, by pod name, pod IP or host IP",
+      "name": "include",
+      "in": "query"
+    },
+    "timeout": {
+      "minimum": 0,
+      "exclusiveMinimum": true,
+      "type": "number",
+      "format": "double",
+      "x-go-name": "TimeoutSeconds",
+      "description": "how long to wait for the check, in seconds, up to the maximum set on the server. Defaults to the timeout set on the server",
+      "name": "timeout",
+      "in": "query"
     }
   }
 }`))
@@ -389,6 +1050,49 @@ func init() {
           "application/json"
         ],
         "operationId": "checkServicePods",
+        "parameters": [
+          {
+            "minimum": 0,
+            "exclusiveMinimum": true,
+            "type": "number",
+            "format": "double",
+            "x-go-name": "TimeoutSeconds",
+            "description": "how long to wait for the check, in seconds, up to the maximum set on the server. Defaults to the timeout set on the server",
+            "name": "timeout",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "csv",
+            "description": "only check these peers, by pod name, pod IP or host IP",
+            "name": "include",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "csv",
+            "description": "leave these peers out, by pod name, pod IP or host IP",
+            "name": "exclude",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "pattern": "^(zone|region|source|role)=.+$",
+              "type": "string"
+            },
+            "collectionFormat": "csv",
+            "description": "only check the peers matching all these key=value filters, on their zone, region, source or role",
+            "name": "filter",
+            "in": "query"
+          }
+        ],
         "responses": {
           "200": {
             "description": "Success, return response",
@@ -406,6 +1110,49 @@ func init() {
           "application/json"
         ],
         "operationId": "checkAllPods",
+        "parameters": [
+          {
+            "minimum": 0,
+            "exclusiveMinimum": true,
+            "type": "number",
+            "format": "double",
+            "x-go-name": "TimeoutSeconds",
+            "description": "how long to wait for the check, in seconds, up to the maximum set on the server. Defaults to the timeout set on the server",
+            "name": "timeout",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "csv",
+            "description": "only check these peers, by pod name, pod IP or host IP",
+            "name": "include",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "csv",
+            "description": "leave these peers out, by pod name, pod IP or host IP",
+            "name": "exclude",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "pattern": "^(zone|region|source|role)=.+$",
+              "type": "string"
+            },
+            "collectionFormat": "csv",
+            "description": "only check the peers matching all these key=value filters, on their zone, region, source or role",
+            "name": "filter",
+            "in": "query"
+          }
+        ],
         "responses": {
           "200": {
             "description": "Success, return response",
@@ -423,6 +1170,49 @@ func init() {
           "application/json"
         ],
         "operationId": "clusterHealth",
+        "parameters": [
+          {
+            "minimum": 0,
+            "exclusiveMinimum": true,
+            "type": "number",
+            "format": "double",
+            "x-go-name": "TimeoutSeconds",
+            "description": "how long to wait for the check, in seconds, up to the maximum set on the server. Defaults to the timeout set on the server",
+            "name": "timeout",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "csv",
+            "description": "only check these peers, by pod name, pod IP or host IP",
+            "name": "include",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "csv",
+            "description": "leave these peers out, by pod name, pod IP or host IP",
+            "name": "exclude",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "pattern": "^(zone|region|source|role)=.+$",
+              "type": "string"
+            },
+            "collectionFormat": "csv",
+            "description": "only check the peers matching all these key=value filters, on their zone, region, source or role",
+            "name": "filter",
+            "in": "query"
+          }
+        ],
         "responses": {
           "200": {
             "description": "Healthy cluster",
@@ -439,6 +1229,77 @@ func init() {
         }
       }
     },
+    "/debug/goldpinger": {
+      "get": {
+        "description": "Report the internal state of the pinger pipeline, for troubleshooting",
+        "produces": [
+          "application/json"
+        ],
+        "operationId": "debug",
+        "responses": {
+          "200": {
+            "description": "return success",
+            "schema": {
+              "$ref": "#/definitions/DiagnosticsResults"
+            }
+          }
+        }
+      }
+    },
+    "/depart": {
+      "post": {
+        "description": "Notifies this instance that a peer is shutting down, so that it reports it as departed rather than unreachable",
+        "consumes": [
+          "application/json"
+        ],
+        "produces": [
+          "application/json"
+        ],
+        "operationId": "depart",
+        "parameters": [
+          {
+            "description": "the instance that is shutting down",
+            "name": "notice",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/DepartureNotice"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success, return response",
+            "schema": {
+              "$ref": "#/definitions/DepartResults"
+            }
+          },
+          "403": {
+            "description": "The notice doesn't come from the departing instance",
+            "schema": {
+              "$ref": "#/definitions/DepartResults"
+            }
+          }
+        }
+      }
+    },
+    "/federation": {
+      "get": {
+        "description": "Calls /cluster_health and /check_all on all the clusters of the federation, and aggregates their health and the latency between them",
+        "produces": [
+          "application/json"
+        ],
+        "operationId": "federation",
+        "responses": {
+          "200": {
+            "description": "Success, return response",
+            "schema": {
+              "$ref": "#/definitions/FederationResults"
+            }
+          }
+        }
+      }
+    },
     "/healthz": {
       "get": {
         "description": "The healthcheck endpoint provides detailed information about the health of a web service. If each of the components required by the service are healthy, then the service is considered healthy and will return a 200 OK response. If any of the components needed by the service are unhealthy, then a 503 Service Unavailable response will be provided.",
@@ -478,6 +1339,46 @@ func init() {
           }
         }
       }
+    },
+    "/readyz": {
+      "get": {
+        "description": "The readiness endpoint succeeds once this instance discovered its peers, started pinging them and collected a first round of results. It reports the status of each component, and returns a 503 Service Unavailable response while any of them isn't ready.",
+        "produces": [
+          "application/json"
+        ],
+        "operationId": "readyz",
+        "responses": {
+          "200": {
+            "description": "Readiness report",
+            "schema": {
+              "$ref": "#/definitions/ReadinessResults"
+            }
+          },
+          "503": {
+            "description": "Instance not ready",
+            "schema": {
+              "$ref": "#/definitions/ReadinessResults"
+            }
+          }
+        }
+      }
+    },
+    "/zone_matrix": {
+      "get": {
+        "description": "Calls /check on all the pods, and aggregates the results into the latency and loss between each pair of zones and regions",
+        "produces": [
+          "application/json"
+        ],
+        "operationId": "zoneMatrix",
+        "responses": {
+          "200": {
+            "description": "Success, return response",
+            "schema": {
+              "$ref": "#/definitions/ZoneMatrixResults"
+            }
+          }
+        }
+      }
     }
   },
   "definitions": {
@@ -515,6 +1416,15 @@ func init() {
         "response": {
           "$ref": "#/definitions/CheckResults"
         },
+        "status": {
+          "description": "ok, failed when the peer couldn't be checked, or unknown when some of its results are stale",
+          "type": "string",
+          "enum": [
+            "ok",
+            "failed",
+            "unknown"
+          ]
+        },
         "status-code": {
           "type": "integer",
           "format": "int32"
@@ -528,12 +1438,6 @@ func init() {
           "type": "boolean",
           "default": false
         },
-        "dnsResults": {
-          "type": "object",
-          "additionalProperties": {
-            "$ref": "#/definitions/DnsResults"
-          }
-        },
         "hosts": {
           "type": "array",
           "items": {
@@ -548,10 +1452,10 @@ func init() {
           "type": "integer",
           "format": "int32"
         },
-        "httpResults": {
+        "probeResults": {
           "type": "object",
           "additionalProperties": {
-            "$ref": "#/definitions/HttpResults"
+            "$ref": "#/definitions/ProbeResults"
           }
         },
         "responses": {
@@ -559,61 +1463,242 @@ func init() {
           "additionalProperties": {
             "$ref": "#/definitions/CheckAllPodResult"
           }
+        }
+      }
+    },
+    "CheckAllResultsHostsItems0": {
+      "type": "object",
+      "properties": {
+        "hostIP": {
+          "type": "string",
+          "format": "ipv4"
+        },
+        "podIP": {
+          "type": "string",
+          "format": "ipv4"
+        },
+        "podName": {
+          "type": "string"
+        }
+      }
+    },
+    "CheckResults": {
+      "type": "object",
+      "properties": {
+        "podResults": {
+          "type": "object",
+          "additionalProperties": {
+            "$ref": "#/definitions/PodResult"
+          }
+        },
+        "probeResults": {
+          "$ref": "#/definitions/ProbeResults"
+        }
+      }
+    },
+    "ClusterHealthResults": {
+      "type": "object",
+      "required": [
+        "OK"
+      ],
+      "properties": {
+        "OK": {
+          "type": "boolean",
+          "default": false
+        },
+        "coverage": {
+          "description": "how many other instances ping each node (its in-degree), keyed by host IP",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "int64"
+          }
+        },
+        "duration-ns": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "generated-at": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "nodesClockSkewed": {
+          "description": "nodes whose clock is off by more than the configured maximum, as seen by most of their peers",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "nodesDeparted": {
+          "description": "nodes whose instance announced that it is shutting down, reported instead of expected",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "nodesHealthy": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "nodesMaintenance": {
+          "description": "unavailable nodes, reported as in maintenance instead of expected, with the maintenance unavailable peer policy",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "nodesTotal": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "nodesUnderObserved": {
+          "description": "nodes pinged by fewer than the configured minimum number of peers",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "nodesUnhealthy": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "ComponentStatus": {
+      "type": "object",
+      "properties": {
+        "OK": {
+          "type": "boolean",
+          "default": false
+        },
+        "message": {
+          "description": "why the component is or isn't ready",
+          "type": "string"
+        }
+      }
+    },
+    "DepartResults": {
+      "type": "object",
+      "properties": {
+        "OK": {
+          "type": "boolean",
+          "default": false
         },
-        "tcpResults": {
-          "type": "object",
-          "additionalProperties": {
-            "$ref": "#/definitions/TcpResults"
-          }
+        "expires-at": {
+          "description": "until when the instance is reported as departed",
+          "type": "string",
+          "format": "date-time"
         }
       }
     },
-    "CheckAllResultsHostsItems0": {
+    "DepartureNotice": {
       "type": "object",
+      "required": [
+        "podIP"
+      ],
       "properties": {
         "hostIP": {
-          "type": "string",
-          "format": "ipv4"
+          "type": "string"
         },
-        "podIP": {
-          "type": "string",
-          "format": "ipv4"
+        "name": {
+          "description": "the name of the instance that is shutting down",
+          "type": "string"
         },
-        "podName": {
+        "podIP": {
           "type": "string"
         }
       }
     },
-    "CheckResults": {
+    "DiagnosticsResults": {
       "type": "object",
       "properties": {
-        "dnsResults": {
-          "$ref": "#/definitions/DnsResults"
+        "active-pingers": {
+          "description": "number of peers being pinged",
+          "type": "integer",
+          "format": "int64"
+        },
+        "generated-at": {
+          "type": "string",
+          "format": "date-time"
         },
-        "httpResults": {
-          "$ref": "#/definitions/HttpResults"
+        "goroutines": {
+          "description": "number of goroutines of the process",
+          "type": "integer",
+          "format": "int64"
         },
-        "podResults": {
+        "last-discovery": {
+          "description": "when discovery last succeeded",
+          "type": "string",
+          "format": "date-time"
+        },
+        "peers": {
+          "description": "the state of the pinger of each peer, by name",
           "type": "object",
           "additionalProperties": {
-            "$ref": "#/definitions/PodResult"
+            "$ref": "#/definitions/PeerDiagnostics"
+          }
+        },
+        "pinger-goroutines": {
+          "description": "number of pinger goroutines still running, including the ones being stopped",
+          "type": "integer",
+          "format": "int64"
+        },
+        "results-channel-capacity": {
+          "description": "capacity of the channel the pingers send their results to",
+          "type": "integer",
+          "format": "int64"
+        },
+        "results-channel-depth": {
+          "description": "number of results waiting for the collector",
+          "type": "integer",
+          "format": "int64"
+        },
+        "stale-peers": {
+          "description": "the peers with no result for longer than the stale threshold",
+          "type": "array",
+          "items": {
+            "type": "string"
           }
         },
-        "tcpResults": {
-          "$ref": "#/definitions/TcpResults"
+        "stale-threshold-ms": {
+          "description": "how long a peer can go without a result before it's stale, in milliseconds",
+          "type": "integer",
+          "format": "int64"
         }
       }
     },
-    "ClusterHealthResults": {
+    "FederationClusterResult": {
+      "type": "object",
+      "properties": {
+        "OK": {
+          "type": "boolean",
+          "default": false
+        },
+        "error": {
+          "type": "string"
+        },
+        "health": {
+          "$ref": "#/definitions/ClusterHealthResults"
+        }
+      }
+    },
+    "FederationResults": {
       "type": "object",
-      "required": [
-        "OK"
-      ],
       "properties": {
         "OK": {
           "type": "boolean",
           "default": false
         },
+        "clusters": {
+          "type": "object",
+          "additionalProperties": {
+            "$ref": "#/definitions/FederationClusterResult"
+          }
+        },
         "duration-ns": {
           "type": "integer",
           "format": "int64"
@@ -622,30 +1707,15 @@ func init() {
           "type": "string",
           "format": "date-time"
         },
-        "nodesHealthy": {
-          "type": "array",
-          "items": {
-            "type": "string"
-          }
-        },
-        "nodesTotal": {
-          "type": "integer",
-          "format": "int64"
-        },
-        "nodesUnhealthy": {
+        "links": {
+          "description": "latency and loss between the gateways of each pair of clusters",
           "type": "array",
           "items": {
-            "type": "string"
+            "$ref": "#/definitions/ZoneLink"
           }
         }
       }
     },
-    "DnsResults": {
-      "type": "object",
-      "additionalProperties": {
-        "$ref": "#/definitions/ProbeResult"
-      }
-    },
     "HealthCheckResults": {
       "type": "object",
       "properties": {
@@ -663,10 +1733,63 @@ func init() {
         }
       }
     },
-    "HttpResults": {
+    "PathResult": {
       "type": "object",
-      "additionalProperties": {
-        "$ref": "#/definitions/ProbeResult"
+      "properties": {
+        "OK": {
+          "type": "boolean",
+          "default": false
+        },
+        "attempts": {
+          "description": "number of attempts, including the retries with --ping-retries",
+          "type": "number",
+          "format": "int64"
+        },
+        "error": {
+          "type": "string"
+        },
+        "error-reason": {
+          "description": "why the call failed",
+          "type": "string",
+          "enum": [
+            "dns",
+            "connection_refused",
+            "connect_timeout",
+            "read_timeout",
+            "reset",
+            "tls",
+            "http_status",
+            "other"
+          ]
+        },
+        "response-time-ms": {
+          "description": "wall clock time in milliseconds",
+          "type": "number",
+          "format": "int64"
+        },
+        "status-code": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "PeerDiagnostics": {
+      "type": "object",
+      "properties": {
+        "last-result": {
+          "description": "when the collector last received a result for the peer",
+          "type": "string",
+          "format": "date-time"
+        },
+        "pinger-started": {
+          "description": "when the pinger of the peer started",
+          "type": "string",
+          "format": "date-time"
+        },
+        "stale": {
+          "description": "true if no result was received for the peer for longer than the stale threshold",
+          "type": "boolean"
+        }
       }
     },
     "PingResults": {
@@ -676,8 +1799,21 @@ func init() {
           "type": "string",
           "format": "date-time"
         },
+        "node_name": {
+          "description": "the name of the node the responder runs on",
+          "type": "string"
+        },
         "received": {
           "$ref": "#/definitions/CallStats"
+        },
+        "server_time": {
+          "description": "the wall clock time of the responder when it handled the request",
+          "type": "string",
+          "format": "date-time"
+        },
+        "source_ip": {
+          "description": "the remote address the responder saw the request come from",
+          "type": "string"
         }
       }
     },
@@ -700,9 +1836,74 @@ func init() {
           "type": "string",
           "format": "ipv4"
         },
+        "age-ms": {
+          "description": "how long ago the ping was made, in milliseconds",
+          "type": "number",
+          "format": "int64"
+        },
+        "attempts": {
+          "description": "number of attempts, including the retries with --ping-retries",
+          "type": "number",
+          "format": "int64"
+        },
+        "clock-offset-ms": {
+          "description": "estimated offset of the peer's clock, in milliseconds",
+          "type": "number",
+          "format": "int64"
+        },
+        "consecutive-failures": {
+          "description": "number of pings failed in a row",
+          "type": "number",
+          "format": "int64"
+        },
+        "consecutive-successes": {
+          "description": "number of pings succeeded in a row",
+          "type": "number",
+          "format": "int64"
+        },
+        "departed": {
+          "description": "true when the peer announced that it is shutting down",
+          "type": "boolean"
+        },
         "error": {
           "type": "string"
         },
+        "error-reason": {
+          "description": "why the call failed",
+          "type": "string",
+          "enum": [
+            "dns",
+            "connection_refused",
+            "connect_timeout",
+            "read_timeout",
+            "reset",
+            "tls",
+            "http_status",
+            "other"
+          ]
+        },
+        "maintenance": {
+          "description": "why the peer or its node is unavailable (terminating, not ready, cordoned or tainted), if it is",
+          "type": "string"
+        },
+        "node-labels": {
+          "description": "the extra labels of the peer's node, selected with --node-labels",
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        },
+        "pathResults": {
+          "description": "per network path results, when pinging over several paths",
+          "type": "object",
+          "additionalProperties": {
+            "$ref": "#/definitions/PathResult"
+          }
+        },
+        "region": {
+          "description": "the region of the peer's node",
+          "type": "string"
+        },
         "response": {
           "$ref": "#/definitions/PingResults"
         },
@@ -711,9 +1912,49 @@ func init() {
           "type": "number",
           "format": "int64"
         },
+        "role": {
+          "description": "the role of the discovery source of the peer, peer or canary",
+          "type": "string"
+        },
+        "source": {
+          "description": "the name of the discovery source of the peer",
+          "type": "string"
+        },
+        "state": {
+          "description": "up, degraded while failing fewer than --peer-down-after pings in a row, down until it succeeds --peer-up-after pings in a row, or flapping",
+          "type": "string",
+          "enum": [
+            "up",
+            "degraded",
+            "down",
+            "flapping"
+          ]
+        },
+        "status": {
+          "description": "ok or failed, or unknown when the result is older than the stale result threshold",
+          "type": "string",
+          "enum": [
+            "ok",
+            "failed",
+            "unknown"
+          ]
+        },
         "status-code": {
           "type": "integer",
           "format": "int32"
+        },
+        "transitions": {
+          "description": "number of transitions between up and down within --peer-flap-window",
+          "type": "number",
+          "format": "int64"
+        },
+        "unexpected-snat": {
+          "description": "true when the peer saw the ping come from another IP than this pod's IP",
+          "type": "boolean"
+        },
+        "zone": {
+          "description": "the zone of the peer's node",
+          "type": "string"
         }
       }
     },
@@ -722,18 +1963,186 @@ func init() {
         "error": {
           "type": "string"
         },
+        "expect": {
+          "type": "string",
+          "enum": [
+            "allow",
+            "deny"
+          ]
+        },
+        "pending": {
+          "description": "true when the target wasn't probed yet",
+          "type": "boolean"
+        },
+        "probe-time": {
+          "description": "when the probe was made",
+          "type": "string",
+          "format": "date-time"
+        },
+        "protocol": {
+          "type": "string"
+        },
         "response-time-ms": {
           "type": "number",
           "format": "int64"
+        },
+        "violation": {
+          "description": "true when a target expected to be denied was reachable",
+          "type": "boolean"
         }
       }
     },
-    "TcpResults": {
+    "ProbeResults": {
       "type": "object",
       "additionalProperties": {
-        "$ref": "#/definitions/ProbeResult"
+        "type": "array",
+        "items": {
+          "$ref": "#/definitions/ProbeResult"
+        }
+      }
+    },
+    "ReadinessResults": {
+      "type": "object",
+      "properties": {
+        "OK": {
+          "type": "boolean",
+          "default": false
+        },
+        "components": {
+          "description": "the status of each component, by name",
+          "type": "object",
+          "additionalProperties": {
+            "$ref": "#/definitions/ComponentStatus"
+          }
+        },
+        "duration-ns": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "generated-at": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "ZoneLink": {
+      "type": "object",
+      "properties": {
+        "avg-response-time-ms": {
+          "description": "average response time of the successful calls, in milliseconds",
+          "type": "number",
+          "format": "double"
+        },
+        "calls": {
+          "description": "number of pings made from the source to the destination",
+          "type": "integer",
+          "format": "int64"
+        },
+        "destination": {
+          "type": "string"
+        },
+        "failures": {
+          "description": "number of failed pings",
+          "type": "integer",
+          "format": "int64"
+        },
+        "loss": {
+          "description": "ratio of failed pings, between 0 and 1",
+          "type": "number",
+          "format": "double"
+        },
+        "max-response-time-ms": {
+          "description": "maximum response time of the successful calls, in milliseconds",
+          "type": "integer",
+          "format": "int64"
+        },
+        "source": {
+          "type": "string"
+        }
+      }
+    },
+    "ZoneMatrixResults": {
+      "type": "object",
+      "properties": {
+        "duration-ns": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "generated-at": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "links": {
+          "description": "latency and loss between each pair of zones",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/ZoneLink"
+          }
+        },
+        "regionLinks": {
+          "description": "latency and loss between each pair of regions",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/ZoneLink"
+          }
+        },
+        "regions": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "zones": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
       }
     }
+  },
+  "parameters": {
+    "exclude": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "collectionFormat": "csv",
+      "description": "leave these peers out, by pod name, pod IP or host IP",
+      "name": "exclude",
+      "in": "query"
+    },
+    "filter": {
+      "type": "array",
+      "items": {
+        "pattern": "^(zone|region|source|role)=.+$",
+        "type": "string"
+      },
+      "collectionFormat": "csv",
+      "description": "only check the peers matching all these key=value filters, on their zone, region, source or role",
+      "name": "filter",
+      "in": "query"
+    },
+    "include": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "collectionFormat": "csv",
+      "description": "only check these peers, by pod name, pod IP or host IP",
+      "name": "include",
+      "in": "query"
+    },
+    "timeout": {
+      "minimum": 0,
+      "exclusiveMinimum": true,
+      "type": "number",
+      "format": "double",
+      "x-go-name": "TimeoutSeconds",
+      "description": "how long to wait for the check, in seconds, up to the maximum set on the server. Defaults to the timeout set on the server",
+      "name": "timeout",
+      "in": "query"
+    }
   }
 }`))
 }
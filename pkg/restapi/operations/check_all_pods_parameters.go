@@ -6,10 +6,15 @@ package operations
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
 )
 
 // NewCheckAllPodsParams creates a new CheckAllPodsParams object
@@ -28,6 +33,32 @@ type CheckAllPodsParams struct {
 
 	// HTTP Request Object
 	HTTPRequest *http.Request `json:"-"`
+
+	/*leave these peers out, by pod name, pod IP or host IP
+	  In: query
+	  Collection Format: csv
+	*/
+	Exclude []string
+
+	/*only check the peers matching all these key=value filters, on their zone, region,
+	  source or role
+	  In: query
+	  Collection Format: csv
+	*/
+	Filter []string
+
+	/*only check these peers, by pod name, pod IP or host IP
+	  In: query
+	  Collection Format: csv
+	*/
+	Include []string
+
+	/*how long to wait for the check, in seconds, up to the maximum set on the server.
+	  Defaults to the timeout set on the server
+	  Minimum: 0
+	  In: query
+	*/
+	TimeoutSeconds *float64
 }
 
 // BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
@@ -39,8 +70,152 @@ func (o *CheckAllPodsParams) BindRequest(r *http.Request, route *middleware.Matc
 
 	o.HTTPRequest = r
 
+	qs := runtime.Values(r.URL.Query())
+
+	qExclude, qhkExclude, _ := qs.GetOK("exclude")
+	if err := o.bindExclude(qExclude, qhkExclude, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qFilter, qhkFilter, _ := qs.GetOK("filter")
+	if err := o.bindFilter(qFilter, qhkFilter, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qInclude, qhkInclude, _ := qs.GetOK("include")
+	if err := o.bindInclude(qInclude, qhkInclude, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qTimeoutSeconds, qhkTimeoutSeconds, _ := qs.GetOK("timeout")
+	if err := o.bindTimeoutSeconds(qTimeoutSeconds, qhkTimeoutSeconds, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
+
+// bindExclude binds and validates array parameter Exclude from query.
+//
+// Arrays are parsed according to CollectionFormat: "csv" (defaults to "csv" when empty).
+func (o *CheckAllPodsParams) bindExclude(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	// CollectionFormat: csv
+	var qvExclude string
+	if len(rawData) > 0 {
+		qvExclude = rawData[len(rawData)-1]
+	}
+
+	excludeIC := swag.SplitByFormat(qvExclude, "csv")
+	if len(excludeIC) == 0 {
+		return nil
+	}
+
+	var excludeIR []string
+	for _, excludeIV := range excludeIC {
+		excludeI := excludeIV
+
+		excludeIR = append(excludeIR, excludeI)
+	}
+
+	o.Exclude = excludeIR
+
+	return nil
+}
+
+// bindFilter binds and validates array parameter Filter from query.
+//
+// Arrays are parsed according to CollectionFormat: "csv" (defaults to "csv" when empty).
+func (o *CheckAllPodsParams) bindFilter(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	// CollectionFormat: csv
+	var qvFilter string
+	if len(rawData) > 0 {
+		qvFilter = rawData[len(rawData)-1]
+	}
+
+	filterIC := swag.SplitByFormat(qvFilter, "csv")
+	if len(filterIC) == 0 {
+		return nil
+	}
+
+	var filterIR []string
+	for i, filterIV := range filterIC {
+		filterI := filterIV
+
+		if err := validate.Pattern(fmt.Sprintf("%s.%v", "filter", i), "query", filterI, `^(zone|region|source|role)=.+$`); err != nil {
+			return err
+		}
+
+		filterIR = append(filterIR, filterI)
+	}
+
+	o.Filter = filterIR
+
+	return nil
+}
+
+// bindInclude binds and validates array parameter Include from query.
+//
+// Arrays are parsed according to CollectionFormat: "csv" (defaults to "csv" when empty).
+func (o *CheckAllPodsParams) bindInclude(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	// CollectionFormat: csv
+	var qvInclude string
+	if len(rawData) > 0 {
+		qvInclude = rawData[len(rawData)-1]
+	}
+
+	includeIC := swag.SplitByFormat(qvInclude, "csv")
+	if len(includeIC) == 0 {
+		return nil
+	}
+
+	var includeIR []string
+	for _, includeIV := range includeIC {
+		includeI := includeIV
+
+		includeIR = append(includeIR, includeI)
+	}
+
+	o.Include = includeIR
+
+	return nil
+}
+
+// bindTimeoutSeconds binds and validates parameter TimeoutSeconds from query.
+func (o *CheckAllPodsParams) bindTimeoutSeconds(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := swag.ConvertFloat64(raw)
+	if err != nil {
+		return errors.InvalidType("timeout", "query", "float64", raw)
+	}
+	o.TimeoutSeconds = &value
+
+	if err := o.validateTimeoutSeconds(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateTimeoutSeconds carries on validations for parameter TimeoutSeconds
+func (o *CheckAllPodsParams) validateTimeoutSeconds(formats strfmt.Registry) error {
+
+	if err := validate.Minimum("timeout", "query", *o.TimeoutSeconds, 0, true); err != nil {
+		return err
+	}
+
+	return nil
+}
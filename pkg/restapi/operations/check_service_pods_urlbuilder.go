@@ -9,11 +9,20 @@ import (
 	"errors"
 	"net/url"
 	golangswaggerpaths "path"
+
+	"github.com/go-openapi/swag"
 )
 
 // CheckServicePodsURL generates an URL for the check service pods operation
 type CheckServicePodsURL struct {
+	Exclude        []string
+	Filter         []string
+	Include        []string
+	TimeoutSeconds *float64
+
 	_basePath string
+	// avoid unkeyed usage
+	_ struct{}
 }
 
 // WithBasePath sets the base path for this url builder, only required when it's different from the
@@ -40,6 +49,69 @@ func (o *CheckServicePodsURL) Build() (*url.URL, error) {
 	_basePath := o._basePath
 	_result.Path = golangswaggerpaths.Join(_basePath, _path)
 
+	qs := make(url.Values)
+
+	var excludeIR []string
+	for _, excludeI := range o.Exclude {
+		excludeIS := excludeI
+		if excludeIS != "" {
+			excludeIR = append(excludeIR, excludeIS)
+		}
+	}
+
+	exclude := swag.JoinByFormat(excludeIR, "csv")
+
+	if len(exclude) > 0 {
+		qsv := exclude[0]
+		if qsv != "" {
+			qs.Set("exclude", qsv)
+		}
+	}
+
+	var filterIR []string
+	for _, filterI := range o.Filter {
+		filterIS := filterI
+		if filterIS != "" {
+			filterIR = append(filterIR, filterIS)
+		}
+	}
+
+	filter := swag.JoinByFormat(filterIR, "csv")
+
+	if len(filter) > 0 {
+		qsv := filter[0]
+		if qsv != "" {
+			qs.Set("filter", qsv)
+		}
+	}
+
+	var includeIR []string
+	for _, includeI := range o.Include {
+		includeIS := includeI
+		if includeIS != "" {
+			includeIR = append(includeIR, includeIS)
+		}
+	}
+
+	include := swag.JoinByFormat(includeIR, "csv")
+
+	if len(include) > 0 {
+		qsv := include[0]
+		if qsv != "" {
+			qs.Set("include", qsv)
+		}
+	}
+
+	var timeoutQ string
+	if o.TimeoutSeconds != nil {
+		timeoutQ = swag.FormatFloat64(*o.TimeoutSeconds)
+	}
+	if timeoutQ != "" {
+		qs.Set("timeout", timeoutQ)
+	}
+
+	_result.RawQuery = qs.Encode()
+
 	return &_result, nil
 }
 
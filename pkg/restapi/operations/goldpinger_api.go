@@ -51,12 +51,27 @@ func NewGoldpingerAPI(spec *loads.Document) *GoldpingerAPI {
 		ClusterHealthHandler: ClusterHealthHandlerFunc(func(params ClusterHealthParams) middleware.Responder {
 			return middleware.NotImplemented("operation ClusterHealth has not yet been implemented")
 		}),
+		DebugHandler: DebugHandlerFunc(func(params DebugParams) middleware.Responder {
+			return middleware.NotImplemented("operation Debug has not yet been implemented")
+		}),
+		DepartHandler: DepartHandlerFunc(func(params DepartParams) middleware.Responder {
+			return middleware.NotImplemented("operation Depart has not yet been implemented")
+		}),
+		FederationHandler: FederationHandlerFunc(func(params FederationParams) middleware.Responder {
+			return middleware.NotImplemented("operation Federation has not yet been implemented")
+		}),
 		HealthzHandler: HealthzHandlerFunc(func(params HealthzParams) middleware.Responder {
 			return middleware.NotImplemented("operation Healthz has not yet been implemented")
 		}),
 		PingHandler: PingHandlerFunc(func(params PingParams) middleware.Responder {
 			return middleware.NotImplemented("operation Ping has not yet been implemented")
 		}),
+		ReadyzHandler: ReadyzHandlerFunc(func(params ReadyzParams) middleware.Responder {
+			return middleware.NotImplemented("operation Readyz has not yet been implemented")
+		}),
+		ZoneMatrixHandler: ZoneMatrixHandlerFunc(func(params ZoneMatrixParams) middleware.Responder {
+			return middleware.NotImplemented("operation ZoneMatrix has not yet been implemented")
+		}),
 	}
 }
 
@@ -99,10 +114,20 @@ type GoldpingerAPI struct {
 	CheckServicePodsHandler CheckServicePodsHandler
 	// ClusterHealthHandler sets the operation handler for the cluster health operation
 	ClusterHealthHandler ClusterHealthHandler
+	// DebugHandler sets the operation handler for the debug operation
+	DebugHandler DebugHandler
+	// DepartHandler sets the operation handler for the depart operation
+	DepartHandler DepartHandler
+	// FederationHandler sets the operation handler for the federation operation
+	FederationHandler FederationHandler
 	// HealthzHandler sets the operation handler for the healthz operation
 	HealthzHandler HealthzHandler
 	// PingHandler sets the operation handler for the ping operation
 	PingHandler PingHandler
+	// ReadyzHandler sets the operation handler for the readyz operation
+	ReadyzHandler ReadyzHandler
+	// ZoneMatrixHandler sets the operation handler for the zone matrix operation
+	ZoneMatrixHandler ZoneMatrixHandler
 
 	// ServeError is called when an error is received, there is a default handler
 	// but you can set your own with this
@@ -189,12 +214,27 @@ func (o *GoldpingerAPI) Validate() error {
 	if o.ClusterHealthHandler == nil {
 		unregistered = append(unregistered, "ClusterHealthHandler")
 	}
+	if o.DebugHandler == nil {
+		unregistered = append(unregistered, "DebugHandler")
+	}
+	if o.DepartHandler == nil {
+		unregistered = append(unregistered, "DepartHandler")
+	}
+	if o.FederationHandler == nil {
+		unregistered = append(unregistered, "FederationHandler")
+	}
 	if o.HealthzHandler == nil {
 		unregistered = append(unregistered, "HealthzHandler")
 	}
 	if o.PingHandler == nil {
 		unregistered = append(unregistered, "PingHandler")
 	}
+	if o.ReadyzHandler == nil {
+		unregistered = append(unregistered, "ReadyzHandler")
+	}
+	if o.ZoneMatrixHandler == nil {
+		unregistered = append(unregistered, "ZoneMatrixHandler")
+	}
 
 	if len(unregistered) > 0 {
 		return fmt.Errorf("missing registration: %s", strings.Join(unregistered, ", "))
@@ -298,11 +338,31 @@ func (o *GoldpingerAPI) initHandlerCache() {
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
+	o.handlers["GET"]["/debug/goldpinger"] = NewDebug(o.context, o.DebugHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/depart"] = NewDepart(o.context, o.DepartHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/federation"] = NewFederation(o.context, o.FederationHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
 	o.handlers["GET"]["/healthz"] = NewHealthz(o.context, o.HealthzHandler)
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/ping"] = NewPing(o.context, o.PingHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/readyz"] = NewReadyz(o.context, o.ReadyzHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/zone_matrix"] = NewZoneMatrix(o.context, o.ZoneMatrixHandler)
 }
 
 // Serve creates a http handler to serve the API over HTTP